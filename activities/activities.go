@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/henvic/wedeploycli/apihelper"
 	"github.com/henvic/wedeploycli/config"
@@ -36,12 +37,101 @@ type Activity struct {
 	Metadata   map[string]interface{} `json:"metadata"`
 }
 
+// CreatedAtTime extracts from the Unix timestamp format and returns the createdAt value
+func (a *Activity) CreatedAtTime() time.Time {
+	return time.Unix(0, a.CreatedAt*int64(time.Millisecond))
+}
+
+// ServiceID related to the activity, if any
+func (a *Activity) ServiceID() string {
+	if s, ok := a.Metadata["serviceId"].(string); ok {
+		return s
+	}
+
+	return ""
+}
+
 // Filter for list
 type Filter struct {
 	Commit   string `json:"commit,omitempty"`
 	GroupUID string `json:"groupUid,omitempty"`
 	Limit    int    `json:"limit,omitempty"`
 	Type     string `json:"type,omitempty"`
+
+	// Types, ServiceID, Since, and Until are filtered on the client-side.
+	// When used, Limit is also applied on the client-side, keeping the most recent activities,
+	// and up to MaxRemoteLimit activities are filtered (see LimitReachedError).
+	Types     []string  `json:"-"`
+	ServiceID string    `json:"-"`
+	Since     time.Time `json:"-"`
+	Until     time.Time `json:"-"`
+}
+
+// MaxRemoteLimit is the number of activities requested when filtering on the client-side
+var MaxRemoteLimit = 9999
+
+// LimitReachedError is returned along with the activities when filtering on the client-side
+// reaches MaxRemoteLimit, as older activities matching the filter might be missing.
+type LimitReachedError struct {
+	Limit int
+}
+
+func (e LimitReachedError) Error() string {
+	return fmt.Sprintf("only the latest %d activities were filtered: older matching activities might be missing",
+		e.Limit)
+}
+
+// IsLimitReached checks if the error is a LimitReachedError
+func IsLimitReached(err error) bool {
+	_, ok := err.(LimitReachedError)
+	return ok
+}
+
+func (f Filter) hasLocalFilters() bool {
+	return len(f.Types) != 0 || f.ServiceID != "" || !f.Since.IsZero() || !f.Until.IsZero()
+}
+
+func (f Filter) match(a Activity) bool {
+	var created = a.CreatedAtTime()
+
+	switch {
+	case len(f.Types) != 0 && !hasType(f.Types, a.Type):
+		return false
+	case f.ServiceID != "" && a.ServiceID() != f.ServiceID:
+		return false
+	case !f.Since.IsZero() && created.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && created.After(f.Until):
+		return false
+	}
+
+	return true
+}
+
+func hasType(types []string, t string) bool {
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f Filter) apply(as []Activity) []Activity {
+	var l = []Activity{}
+
+	for _, a := range as {
+		if f.match(a) {
+			l = append(l, a)
+		}
+	}
+
+	if f.Limit > 0 && len(l) > f.Limit {
+		l = l[len(l)-f.Limit:]
+	}
+
+	return l
 }
 
 const (
@@ -191,7 +281,13 @@ func (c *Client) List(ctx context.Context, projectID string, f Filter) (activiti
 	}
 
 	var request = c.Client.URL(ctx, "/projects/"+url.PathEscape(projectID)+"/activities")
-	apihelper.ParamsFromJSON(request, f)
+	var remote = f
+
+	if f.hasLocalFilters() {
+		remote.Limit = MaxRemoteLimit
+	}
+
+	apihelper.ParamsFromJSON(request, remote)
 
 	c.Client.Auth(request)
 
@@ -205,9 +301,25 @@ func (c *Client) List(ctx context.Context, projectID string, f Filter) (activiti
 		return activities[i].CreatedAt < activities[j].CreatedAt
 	})
 
+	if err != nil || !f.hasLocalFilters() {
+		return activities, err
+	}
+
+	var reached = len(activities) >= MaxRemoteLimit
+	activities = f.apply(activities)
+
+	if reached {
+		err = LimitReachedError{MaxRemoteLimit}
+	}
+
 	return activities, err
 }
 
+// Message for a given activity in a human-readable way
+func Message(a Activity) (string, error) {
+	return getActivityMessage(a, activityTemplates)
+}
+
 // PrettyPrintList prints the activities in a formatted way
 func PrettyPrintList(activities []Activity) {
 	for _, a := range activities {
@@ -231,3 +343,120 @@ func getActivityMessage(a Activity, template map[string]string) (string, error)
 
 	return templates.Execute(at, a)
 }
+
+// PoolingInterval is the default time between checking for new activities.
+var PoolingInterval = 5 * time.Second
+
+// Watcher for new activities on one or more projects
+type Watcher struct {
+	Client          *Client
+	PoolingInterval time.Duration
+
+	Projects []string
+	Filter   Filter
+
+	// Handler is called for each new activity, in order.
+	Handler func(a Activity) error
+
+	// ErrorHandler is called when listing activities or handling them fails.
+	ErrorHandler func(err error)
+
+	// activities seen on the last listing of each project, set once the project is primed by its first successful listing
+	seen  map[string]map[string]bool
+	since map[string]int64
+}
+
+// Watch for new activities until the context is canceled.
+// Activities that already exist when watching starts are not handled,
+// even if listing them only succeeds on a later attempt.
+func (w *Watcher) Watch(ctx context.Context, wectx config.Context) {
+	if w.Client == nil {
+		w.Client = New(wectx)
+	}
+
+	if w.PoolingInterval == 0 {
+		w.PoolingInterval = PoolingInterval
+	}
+
+	w.seen = map[string]map[string]bool{}
+	w.since = map[string]int64{}
+
+	w.pool(ctx)
+
+	ticker := time.NewTicker(w.PoolingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.pool(ctx)
+		}
+	}
+}
+
+func (w *Watcher) pool(ctx context.Context) {
+	for _, p := range w.Projects {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := w.poolProject(ctx, p); err != nil && ctx.Err() == nil {
+			w.handleError(err)
+		}
+	}
+}
+
+func (w *Watcher) poolProject(ctx context.Context, projectID string) error {
+	var f = w.Filter
+
+	if since, ok := w.since[projectID]; ok {
+		f.Since = time.Unix(0, since*int64(time.Millisecond))
+	}
+
+	var as, err = w.Client.List(ctx, projectID, f)
+
+	// the time window moves forward on each listing, so reaching the limit is only expected on the first one
+	if err != nil && !IsLimitReached(err) {
+		return err
+	}
+
+	var seen, primed = w.seen[projectID]
+
+	// only activities since the most recent one seen are listed on the next time,
+	// so the IDs of older activities are not kept
+	var next = make(map[string]bool, len(as))
+	w.seen[projectID] = next
+
+	for _, a := range as {
+		next[a.ID] = true
+
+		if seen[a.ID] {
+			continue
+		}
+
+		if a.CreatedAt > w.since[projectID] {
+			w.since[projectID] = a.CreatedAt
+		}
+
+		if !primed || w.Handler == nil {
+			continue
+		}
+
+		if err := w.Handler(a); err != nil {
+			w.handleError(err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Watcher) handleError(err error) {
+	if w.ErrorHandler != nil {
+		w.ErrorHandler(err)
+		return
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%+v\n", err)
+}
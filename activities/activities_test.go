@@ -0,0 +1,359 @@
+package activities
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+var (
+	wectx  config.Context
+	client *Client
+
+	update bool
+)
+
+func init() {
+	flag.BoolVar(&update, "update", false, "update golden files")
+}
+
+func TestMain(m *testing.M) {
+	var err error
+	wectx, err = config.Setup("mocks/.lcp")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := wectx.SetEndpoint(defaults.CloudRemote); err != nil {
+		panic(err)
+	}
+
+	client = New(wectx)
+	os.Exit(m.Run())
+}
+
+func getIDs(as []Activity) (ids []string) {
+	for _, a := range as {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+func assertIDs(t *testing.T, want []string, as []Activity) {
+	var got = getIDs(as)
+
+	if len(want) != len(got) {
+		t.Errorf("Wanted activities %v, got %v instead", want, got)
+		return
+	}
+
+	for i := range want {
+		if want[i] != got[i] {
+			t.Errorf("Wanted activities %v, got %v instead", want, got)
+			return
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("limit") != "2" {
+				t.Errorf("Expected limit to be sent to the server")
+			}
+
+			tdata.ServerJSONFileHandler("mocks/activities_response.json")(w, r)
+		})
+
+	var as, err = client.List(context.Background(), "acme-prd", Filter{
+		Limit: 2,
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	// sorting happens on the client-side
+	assertIDs(t, []string{"1", "2", "3", "4"}, as)
+}
+
+func TestListLocalFilters(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("limit") != "9999" {
+				t.Errorf("Expected limit to be applied on the client-side, got %v instead", r.URL.Query().Get("limit"))
+			}
+
+			tdata.ServerJSONFileHandler("mocks/activities_response.json")(w, r)
+		})
+
+	var cases = []struct {
+		filter Filter
+		want   []string
+	}{
+		{
+			Filter{
+				Since: time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC),
+				Until: time.Date(2019, 9, 30, 0, 0, 0, 0, time.UTC),
+			},
+			[]string{"1", "2"},
+		},
+		{
+			Filter{
+				Types: []string{DeployFailed, DeploySucceeded},
+			},
+			[]string{"2", "3"},
+		},
+		{
+			Filter{
+				ServiceID: "liferay",
+				Limit:     1,
+			},
+			[]string{"3"},
+		},
+		{
+			Filter{
+				ServiceID: "search",
+			},
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		var as, err = client.List(context.Background(), "acme-prd", c.filter)
+
+		if err != nil {
+			t.Errorf("Expected no error, got %v instead", err)
+		}
+
+		assertIDs(t, c.want, as)
+	}
+}
+
+func TestListLocalFiltersLimitReached(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var defaultMaxRemoteLimit = MaxRemoteLimit
+	MaxRemoteLimit = 4
+
+	defer func() {
+		MaxRemoteLimit = defaultMaxRemoteLimit
+	}()
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities", tdata.ServerJSONFileHandler("mocks/activities_response.json"))
+
+	var as, err = client.List(context.Background(), "acme-prd", Filter{
+		Types: []string{DeployFailed, DeploySucceeded},
+	})
+
+	if !IsLimitReached(err) {
+		t.Errorf("Expected limit reached error, got %v instead", err)
+	}
+
+	assertIDs(t, []string{"2", "3"}, as)
+}
+
+func TestActivityHelpers(t *testing.T) {
+	var a = Activity{
+		CreatedAt: 1567332000000,
+		ProjectID: "acme-prd",
+		Type:      CustomDomainUpdated,
+		Metadata: map[string]interface{}{
+			"serviceId": "webserver",
+		},
+	}
+
+	if want := time.Date(2019, 9, 1, 10, 0, 0, 0, time.UTC); !want.Equal(a.CreatedAtTime()) {
+		t.Errorf("Wanted created at time to be %v, got %v instead", want, a.CreatedAtTime())
+	}
+
+	if a.ServiceID() != "webserver" {
+		t.Errorf("Wanted service ID to be webserver, got %v instead", a.ServiceID())
+	}
+
+	var msg, err = Message(a)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if want := "webserver custom domain updated on project acme-prd"; msg != want {
+		t.Errorf("Wanted message to be %v, got %v instead", want, msg)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var requests = 0
+	var m sync.Mutex
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities",
+		func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			defer m.Unlock()
+			requests++
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+			if requests == 1 {
+				_, _ = fmt.Fprintln(w, `[{"id": "1", "createdAt": 1567332000000, "projectId": "acme-prd", "type": "DEPLOY_SUCCEEDED"}]`)
+				return
+			}
+
+			_, _ = fmt.Fprintln(w, tdata.FromFile("mocks/activities_response.json"))
+		})
+
+	var handled []Activity
+
+	var w = &Watcher{
+		Projects:        []string{"acme-prd"},
+		PoolingInterval: time.Millisecond,
+		Filter: Filter{
+			Types: []string{DeployFailed, DeploySucceeded, ProjectRestarted},
+		},
+		Handler: func(a Activity) error {
+			handled = append(handled, a)
+			return nil
+		},
+		ErrorHandler: func(err error) {
+			t.Errorf("Expected no error, got %v instead", err)
+		},
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w.Watch(ctx, wectx)
+
+	// activity 1 existed before watching started, so it is not handled
+	assertIDs(t, []string{"2", "3", "4"}, handled)
+}
+
+func TestWatcherKeepsOnlyLastListingSeen(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var requests = 0
+	var m sync.Mutex
+
+	var responses = []string{
+		`[{"id": "1", "createdAt": 1567332000000, "type": "DEPLOY_SUCCEEDED"}]`,
+		`[{"id": "1", "createdAt": 1567332000000, "type": "DEPLOY_SUCCEEDED"},
+		  {"id": "2", "createdAt": 1567332001000, "type": "DEPLOY_SUCCEEDED"}]`,
+		`[{"id": "2", "createdAt": 1567332001000, "type": "DEPLOY_SUCCEEDED"},
+		  {"id": "3", "createdAt": 1567332002000, "type": "DEPLOY_SUCCEEDED"}]`,
+	}
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities",
+		func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			defer m.Unlock()
+
+			if requests < len(responses) {
+				requests++
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			_, _ = fmt.Fprintln(w, responses[requests-1])
+		})
+
+	var handled []Activity
+
+	var w = &Watcher{
+		Projects:        []string{"acme-prd"},
+		PoolingInterval: time.Millisecond,
+		Handler: func(a Activity) error {
+			handled = append(handled, a)
+			return nil
+		},
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w.Watch(ctx, wectx)
+
+	assertIDs(t, []string{"2", "3"}, handled)
+
+	// activity 2 is older than the most recent activity seen, so it isn't listed anymore
+	if seen := w.seen["acme-prd"]; len(seen) != 1 || !seen["3"] {
+		t.Errorf("Expected only activities of the last listing to be kept, got %v instead", seen)
+	}
+}
+
+func TestWatcherFailedInitialListing(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var requests = 0
+	var m sync.Mutex
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities",
+		func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			defer m.Unlock()
+			requests++
+
+			if requests == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+			if requests == 2 {
+				_, _ = fmt.Fprintln(w, `[{"id": "1", "createdAt": 1567332000000, "projectId": "acme-prd", "type": "DEPLOY_SUCCEEDED"}]`)
+				return
+			}
+
+			_, _ = fmt.Fprintln(w, tdata.FromFile("mocks/activities_response.json"))
+		})
+
+	var handled []Activity
+	var failures = 0
+
+	var w = &Watcher{
+		Projects:        []string{"acme-prd"},
+		PoolingInterval: time.Millisecond,
+		Handler: func(a Activity) error {
+			handled = append(handled, a)
+			return nil
+		},
+		ErrorHandler: func(err error) {
+			failures++
+		},
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w.Watch(ctx, wectx)
+
+	if failures != 1 {
+		t.Errorf("Expected listing error to be handled once, got %d instead", failures)
+	}
+
+	// activity 1 existed before the first successful listing, so it is not handled
+	assertIDs(t, []string{"2", "3", "4"}, handled)
+}
@@ -0,0 +1,207 @@
+package activities
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Actor who triggered the activity, if known
+func (a *Activity) Actor() string {
+	for _, k := range []string{"userEmail", "email", "userId"} {
+		if s, ok := a.Metadata[k].(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// Report of activities grouped by type, actor, and service
+type Report struct {
+	Since  time.Time
+	Until  time.Time
+	Groups []ReportGroup
+}
+
+// ReportGroup of activities with the same type, actor, and service
+type ReportGroup struct {
+	Type       string
+	Actor      string
+	Service    string
+	Activities []Activity
+}
+
+// NewReport groups the activities by type, actor, and service
+func NewReport(as []Activity, since, until time.Time) Report {
+	var r = Report{
+		Since: since,
+		Until: until,
+	}
+
+	var groups = map[[3]string]*ReportGroup{}
+	var keys = [][3]string{}
+
+	for _, a := range as {
+		var k = [3]string{a.Type, a.Actor(), a.ServiceID()}
+		var g, ok = groups[k]
+
+		if !ok {
+			g = &ReportGroup{
+				Type:    k[0],
+				Actor:   k[1],
+				Service: k[2],
+			}
+
+			groups[k] = g
+			keys = append(keys, k)
+		}
+
+		g.Activities = append(g.Activities, a)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		for n := range keys[i] {
+			if keys[i][n] != keys[j][n] {
+				return keys[i][n] < keys[j][n]
+			}
+		}
+
+		return false
+	})
+
+	for _, k := range keys {
+		var g = groups[k]
+
+		sort.SliceStable(g.Activities, func(i, j int) bool {
+			return g.Activities[i].CreatedAt < g.Activities[j].CreatedAt
+		})
+
+		r.Groups = append(r.Groups, *g)
+	}
+
+	return r
+}
+
+const reportTimeFormat = "2006-01-02 15:04:05 MST"
+
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format(reportTimeFormat)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// CSV writes the report with one line for each activity
+func (r Report) CSV(w io.Writer) error {
+	var cw = csv.NewWriter(w)
+
+	if err := cw.Write([]string{"time", "type", "actor", "service", "project", "message"}); err != nil {
+		return err
+	}
+
+	for _, g := range r.Groups {
+		for _, a := range g.Activities {
+			var msg, err = Message(a)
+
+			if err != nil {
+				return err
+			}
+
+			err = cw.Write([]string{
+				formatReportTime(a.CreatedAtTime()),
+				g.Type,
+				g.Actor,
+				g.Service,
+				a.ProjectID,
+				msg,
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Markdown writes the report with a summary table followed by the activities of each group
+func (r Report) Markdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Activities report\n\n")
+	_, _ = fmt.Fprintf(&b, "From %s to %s.\n\n", formatReportTime(r.Since), formatReportTime(r.Until))
+
+	if len(r.Groups) == 0 {
+		b.WriteString("No activities found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("| Type | Actor | Service | Count |\n")
+	b.WriteString("| --- | --- | --- | ---: |\n")
+
+	for _, g := range r.Groups {
+		_, _ = fmt.Fprintf(&b, "| %s | %s | %s | %d |\n",
+			escapeMarkdown(friendlyType(g.Type)),
+			escapeMarkdown(orNone(g.Actor)),
+			escapeMarkdown(orNone(g.Service)),
+			len(g.Activities))
+	}
+
+	for _, g := range r.Groups {
+		_, _ = fmt.Fprintf(&b, "\n## %s (actor: %s, service: %s)\n\n",
+			escapeMarkdown(friendlyType(g.Type)),
+			escapeMarkdown(orNone(g.Actor)),
+			escapeMarkdown(orNone(g.Service)))
+
+		for _, a := range g.Activities {
+			var msg, err = Message(a)
+
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(&b, "- %s: %s\n", formatReportTime(a.CreatedAtTime()), escapeMarkdown(msg))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func friendlyType(t string) string {
+	if f, ok := Friendly[t]; ok {
+		return f
+	}
+
+	return t
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
@@ -0,0 +1,101 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/stringlib"
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+func getReport(t *testing.T) Report {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme-prd/activities",
+		tdata.ServerJSONFileHandler("mocks/activities_response.json"))
+
+	var since = time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)
+	var until = time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	var as, err = client.List(context.Background(), "acme-prd", Filter{
+		Since: since,
+		Until: until,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	return NewReport(as, since, until)
+}
+
+func TestReportGroups(t *testing.T) {
+	var r = getReport(t)
+
+	var want = []ReportGroup{
+		{Type: CustomDomainUpdated, Actor: "admin@example.com", Service: "webserver"},
+		{Type: DeployFailed, Actor: "dev@example.com", Service: "liferay"},
+		{Type: DeploySucceeded, Actor: "admin@example.com", Service: "liferay"},
+	}
+
+	if len(r.Groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %d instead", len(want), len(r.Groups))
+	}
+
+	for i, w := range want {
+		var g = r.Groups[i]
+
+		if g.Type != w.Type || g.Actor != w.Actor || g.Service != w.Service || len(g.Activities) != 1 {
+			t.Errorf("Expected group %d to be %+v, got %+v instead", i, w, g)
+		}
+	}
+}
+
+func TestReportCSV(t *testing.T) {
+	var r = getReport(t)
+	var b bytes.Buffer
+
+	if err := r.CSV(&b); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if update {
+		tdata.ToFile("mocks/report.csv", b.String())
+	}
+
+	stringlib.AssertSimilar(t, tdata.FromFile("mocks/report.csv"), b.String())
+}
+
+func TestReportMarkdown(t *testing.T) {
+	var r = getReport(t)
+	var b bytes.Buffer
+
+	if err := r.Markdown(&b); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if update {
+		tdata.ToFile("mocks/report.md", b.String())
+	}
+
+	stringlib.AssertSimilar(t, tdata.FromFile("mocks/report.md"), b.String())
+}
+
+func TestReportMarkdownEmpty(t *testing.T) {
+	var r = NewReport(nil, time.Time{}, time.Time{})
+	var b bytes.Buffer
+
+	if err := r.Markdown(&b); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	var want = "# Activities report\n\nFrom - to -.\n\nNo activities found.\n"
+
+	if b.String() != want {
+		t.Errorf("Expected empty report to be %q, got %q instead", want, b.String())
+	}
+}
@@ -0,0 +1,291 @@
+// Package asciicast records and replays terminal sessions in the asciinema v2 format.
+//
+// See https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md
+package asciicast
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+)
+
+// Version of the asciicast format
+const Version = 2
+
+// Output event type
+const Output = "o"
+
+// Header of a recording
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event of a recording
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// MarshalJSON encodes the event as a [time, type, data] array
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Time, e.Type, e.Data})
+}
+
+// UnmarshalJSON decodes the event from a [time, type, data] array
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	if len(raw) != 3 {
+		return fmt.Errorf("expected event with 3 elements, got %d instead", len(raw))
+	}
+
+	if err := json.Unmarshal(raw[0], &e.Time); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw[1], &e.Type); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw[2], &e.Data)
+}
+
+// Recorder of terminal output
+type Recorder struct {
+	w     io.Writer
+	start time.Time
+	m     sync.Mutex
+	err   error
+}
+
+var now = time.Now
+
+// NewRecorder writes the header and returns a recorder for the events
+func NewRecorder(w io.Writer, h Header) (*Recorder, error) {
+	var r = &Recorder{
+		w:     w,
+		start: now(),
+	}
+
+	h.Version = Version
+
+	if h.Timestamp == 0 {
+		h.Timestamp = r.start.Unix()
+	}
+
+	var b, err = json.Marshal(h)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", b); err != nil {
+		return nil, errwrap.Wrapf("can't write recording header: {{err}}", err)
+	}
+
+	return r, nil
+}
+
+// Record an event
+func (r *Recorder) Record(eventType string, data []byte) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	var e = Event{
+		Time: now().Sub(r.start).Seconds(),
+		Type: eventType,
+		Data: string(data),
+	}
+
+	var b, err = json.Marshal(e)
+
+	if err == nil {
+		_, err = fmt.Fprintf(r.w, "%s\n", b)
+	}
+
+	if err != nil {
+		r.err = errwrap.Wrapf("can't write recording: {{err}}", err)
+	}
+
+	return r.err
+}
+
+// Err returns the first error found while recording
+func (r *Recorder) Err() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.err
+}
+
+// Output returns a writer that writes to w and records what is written as output
+func (r *Recorder) Output(w io.Writer) io.Writer {
+	return &outputWriter{
+		w: w,
+		r: r,
+	}
+}
+
+type outputWriter struct {
+	w io.Writer
+	r *Recorder
+}
+
+func (o *outputWriter) Write(b []byte) (int, error) {
+	n, err := o.w.Write(b)
+
+	// recording failures are checked once the session ends, instead of breaking it
+	_ = o.r.Record(Output, b[:n])
+	return n, err
+}
+
+// Reader of recordings
+type Reader struct {
+	Header Header
+	s      *bufio.Scanner
+	line   int
+}
+
+// NewReader reads the header of a recording
+func NewReader(r io.Reader) (*Reader, error) {
+	var s = bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, errors.New("empty recording")
+	}
+
+	var rr = &Reader{s: s, line: 1}
+
+	if err := json.Unmarshal(s.Bytes(), &rr.Header); err != nil {
+		return nil, errwrap.Wrapf("can't read recording header: {{err}}", err)
+	}
+
+	if rr.Header.Version != Version {
+		return nil, fmt.Errorf("unsupported recording version %d (only version %d is supported)", rr.Header.Version, Version)
+	}
+
+	return rr, nil
+}
+
+// Next event, or io.EOF at the end of the recording
+func (r *Reader) Next() (e Event, err error) {
+	for r.s.Scan() {
+		r.line++
+
+		if len(r.s.Bytes()) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(r.s.Bytes(), &e); err != nil {
+			return e, fmt.Errorf("can't read recording event on line %d: %v", r.line, err)
+		}
+
+		return e, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return e, err
+	}
+
+	return e, io.EOF
+}
+
+// Player replays recordings
+type Player struct {
+	// Speed multiplier (defaults to 1)
+	Speed float64
+
+	// MaxIdle limits the time waiting between events when not zero
+	MaxIdle time.Duration
+
+	// Sleep is used to wait between events (defaults to a context aware time.Sleep)
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Play the output events of a recording
+func (p *Player) Play(ctx context.Context, w io.Writer, r *Reader) error {
+	var speed = p.Speed
+
+	if speed <= 0 {
+		speed = 1
+	}
+
+	var sleep = p.Sleep
+
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	var last float64
+
+	for {
+		e, err := r.Next()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if e.Type != Output {
+			continue
+		}
+
+		var wait = time.Duration((e.Time - last) / speed * float64(time.Second))
+		last = e.Time
+
+		if p.MaxIdle > 0 && wait > p.MaxIdle {
+			wait = p.MaxIdle
+		}
+
+		if wait > 0 {
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+
+		if _, err := io.WriteString(w, e.Data); err != nil {
+			return err
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	var t = time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package asciicast
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+var update bool
+
+func init() {
+	flag.BoolVar(&update, "update", false, "update golden files")
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
+func TestRecord(t *testing.T) {
+	var defaultNow = now
+	var clock = time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	now = func() time.Time {
+		return clock
+	}
+
+	defer func() {
+		now = defaultNow
+	}()
+
+	var b bytes.Buffer
+
+	r, err := NewRecorder(&b, Header{
+		Width:  80,
+		Height: 24,
+		Title:  "admin@example.com on liferay-acme-prd.lfr.cloud",
+		Env: map[string]string{
+			"TERM": "xterm-256color",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error creating recorder, got %v instead", err)
+	}
+
+	var out bytes.Buffer
+	var w = r.Output(&out)
+
+	clock = clock.Add(500 * time.Millisecond)
+	_, _ = w.Write([]byte("$ "))
+
+	clock = clock.Add(1250 * time.Millisecond)
+	_, _ = w.Write([]byte("ls\r\n\"quoted\" \x1b[1mbold\x1b[0m\r\n"))
+
+	if err := r.Err(); err != nil {
+		t.Errorf("Expected no recording error, got %v instead", err)
+	}
+
+	if want := "$ ls\r\n\"quoted\" \x1b[1mbold\x1b[0m\r\n"; out.String() != want {
+		t.Errorf("Wanted output to be %q, got %q instead", want, out.String())
+	}
+
+	if update {
+		tdata.ToFile("mocks/session.cast", b.String())
+	}
+
+	if want := tdata.FromFile("mocks/session.cast"); b.String() != want {
+		t.Errorf("Wanted %v, got %v instead", want, b.String())
+	}
+}
+
+func TestReader(t *testing.T) {
+	f, err := os.Open("mocks/session.cast")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	r, err := NewReader(f)
+
+	if err != nil {
+		t.Fatalf("Expected no error reading header, got %v instead", err)
+	}
+
+	if r.Header.Width != 80 || r.Header.Height != 24 || r.Header.Timestamp != 1569931200 {
+		t.Errorf("Unexpected header %+v", r.Header)
+	}
+
+	var want = []Event{
+		{Time: 0.5, Type: Output, Data: "$ "},
+		{Time: 1.75, Type: Output, Data: "ls\r\n\"quoted\" \x1b[1mbold\x1b[0m\r\n"},
+	}
+
+	var got []Event
+
+	for {
+		e, err := r.Next()
+
+		if err != nil {
+			break
+		}
+
+		got = append(got, e)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+}
+
+func TestReaderUnsupportedVersion(t *testing.T) {
+	var _, err = NewReader(strings.NewReader(`{"version": 1, "width": 80, "height": 24}`))
+
+	if err == nil || !strings.Contains(err.Error(), "unsupported recording version 1") {
+		t.Errorf("Expected unsupported version error, got %v instead", err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	var recording = `{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[1.0, "i", "ignored input"]
+[2.5, "o", "b"]
+[12.5, "o", "c"]
+`
+
+	r, err := NewReader(strings.NewReader(recording))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var waits []time.Duration
+
+	var p = &Player{
+		Speed:   2,
+		MaxIdle: 3 * time.Second,
+		Sleep: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	}
+
+	var b bytes.Buffer
+
+	if err := p.Play(context.Background(), &b, r); err != nil {
+		t.Errorf("Expected no error playing, got %v instead", err)
+	}
+
+	if b.String() != "abc" {
+		t.Errorf("Wanted output abc, got %v instead", b.String())
+	}
+
+	var want = []time.Duration{250 * time.Millisecond, time.Second, 3 * time.Second}
+
+	if !reflect.DeepEqual(want, waits) {
+		t.Errorf("Wanted waits %v, got %v instead", want, waits)
+	}
+}
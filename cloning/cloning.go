@@ -0,0 +1,90 @@
+// Package cloning copies the configuration of a service to a new service.
+package cloning
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/services"
+)
+
+// Options for cloning
+type Options struct {
+	// ExcludeEnv patterns of environment variable names not to copy (case-insensitive)
+	ExcludeEnv []string
+
+	// Domains copies the custom domains
+	Domains bool
+}
+
+// Excluded tells whether an environment variable should not be copied
+func (o Options) Excluded(name string) bool {
+	name = strings.ToUpper(name)
+
+	for _, p := range o.ExcludeEnv {
+		if ok, _ := path.Match(strings.ToUpper(p), name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Body for creating a new service with the configuration of an existing one
+func Body(serviceID string, s services.Service, envs []services.EnvironmentVariable, o Options) services.CreateBody {
+	var body = services.CreateBody{
+		ServiceID: serviceID,
+		Image:     s.Image,
+		CPU:       s.CPU,
+		Memory:    s.Memory,
+		Scale:     s.Scale,
+	}
+
+	if o.Domains {
+		body.CustomDomains = s.CustomDomains
+	}
+
+	for _, e := range envs {
+		if o.Excluded(e.Name) {
+			continue
+		}
+
+		if body.Env == nil {
+			body.Env = map[string]string{}
+		}
+
+		body.Env[e.Name] = e.Value
+	}
+
+	return body
+}
+
+// Cloner of services
+type Cloner struct {
+	Client *services.Client
+}
+
+// Clone a service to a given project and service ID
+func (c *Cloner) Clone(ctx context.Context, fromProject, fromService, toProject, toService string, o Options) (services.Service, error) {
+	var s, err = c.Client.Get(ctx, fromProject, fromService)
+
+	if err != nil {
+		return services.Service{}, errwrap.Wrapf("can't get service to clone: {{err}}", err)
+	}
+
+	envs, err := c.Client.GetEnvironmentVariables(ctx, fromProject, fromService)
+
+	if err != nil {
+		return services.Service{}, errwrap.Wrapf("can't get environment variables of service to clone: {{err}}", err)
+	}
+
+	created, err := c.Client.Create(ctx, toProject, Body(toService, s, envs, o))
+
+	if err != nil {
+		return services.Service{}, errwrap.Wrapf("can't create service: {{err}}", err)
+	}
+
+	return created, nil
+}
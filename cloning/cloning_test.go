@@ -0,0 +1,154 @@
+package cloning
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/tdata"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+var (
+	wectx  config.Context
+	client *services.Client
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	wectx, err = config.Setup("mocks/.lcp")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := wectx.SetEndpoint(defaults.CloudRemote); err != nil {
+		panic(err)
+	}
+
+	client = services.New(wectx)
+	os.Exit(m.Run())
+}
+
+var search = services.Service{
+	ServiceID:     "search",
+	Image:         "liferaycloud/elasticsearch:6.8.4-3.0.7",
+	Scale:         3,
+	CPU:           json.Number("2"),
+	Memory:        json.Number("4096"),
+	CustomDomains: []string{"search.acme.com"},
+}
+
+var envs = []services.EnvironmentVariable{
+	{Name: "ES_JAVA_OPTS", Value: "-Xms2g -Xmx2g"},
+	{Name: "LCP_UAT_ONLY", Value: "true"},
+	{Name: "lcp_uat_debug", Value: "1"},
+}
+
+func TestBody(t *testing.T) {
+	var got = Body("search2", search, envs, Options{
+		ExcludeEnv: []string{"LCP_UAT_*"},
+	})
+
+	var want = services.CreateBody{
+		ServiceID: "search2",
+		Image:     "liferaycloud/elasticsearch:6.8.4-3.0.7",
+		Scale:     3,
+		CPU:       json.Number("2"),
+		Memory:    json.Number("4096"),
+		Env: map[string]string{
+			"ES_JAVA_OPTS": "-Xms2g -Xmx2g",
+		},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Body does not match with wanted structure.")
+		t.Errorf(pretty.Compare(want, got))
+	}
+}
+
+func TestBodyWithDomains(t *testing.T) {
+	var got = Body("search", search, nil, Options{Domains: true})
+
+	if !reflect.DeepEqual(got.CustomDomains, search.CustomDomains) {
+		t.Errorf("Wanted domains %v, got %v instead", search.CustomDomains, got.CustomDomains)
+	}
+
+	if got.Env != nil {
+		t.Errorf("Expected no environment variables, got %v instead", got.Env)
+	}
+}
+
+func TestClone(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme-uat/services/search",
+		tdata.ServerJSONFileHandler("mocks/service_response.json"))
+	servertest.Mux.HandleFunc("/projects/acme-uat/services/search/environment-variables",
+		tdata.ServerJSONFileHandler("mocks/envs_response.json"))
+
+	var body services.CreateBody
+
+	servertest.Mux.HandleFunc("/projects/acme-dev/services",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected method %v, got %v instead", http.MethodPost, r.Method)
+			}
+
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Expected no error decoding body, got %v instead", err)
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			_, _ = w.Write([]byte(tdata.FromFile("mocks/create_response.json")))
+		})
+
+	var c = Cloner{
+		Client: client,
+	}
+
+	var s, err = c.Clone(context.Background(), "acme-uat", "search", "acme-dev", "search", Options{
+		ExcludeEnv: []string{"lcp_uat_*"},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if s.ServiceID != "search" || s.Health != "unknown" {
+		t.Errorf("Unexpected created service %+v", s)
+	}
+
+	var want = Body("search", search, envs[:1], Options{})
+
+	if !reflect.DeepEqual(want, body) {
+		t.Errorf("Create body does not match with wanted structure.")
+		t.Errorf(pretty.Compare(want, body))
+	}
+}
+
+func TestCloneNotFound(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme-uat/services/search",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+	var c = Cloner{
+		Client: client,
+	}
+
+	if _, err := c.Clone(context.Background(), "acme-uat", "search", "acme-dev", "search", Options{}); err == nil {
+		t.Errorf("Expected error, got nil instead")
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/color"
 	"github.com/henvic/wedeploycli/config"
 	"github.com/henvic/wedeploycli/defaults"
 	"github.com/henvic/wedeploycli/flagsfromhost"
@@ -18,6 +21,8 @@ import (
 	"github.com/henvic/wedeploycli/login"
 	"github.com/henvic/wedeploycli/metrics"
 	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/usertoken"
+	"github.com/henvic/wedeploycli/verbose"
 	"github.com/spf13/cobra"
 )
 
@@ -66,6 +71,8 @@ type SetupHost struct {
 	parsed *flagsfromhost.FlagsFromHost
 
 	tmpEnv string
+
+	expiryChecked bool
 }
 
 // Pattern for the host and flags
@@ -528,7 +535,7 @@ func (s *SetupHost) promptMissingProjectOrService() (err error) {
 
 func (s *SetupHost) verifyCmdReqAuth() error {
 	if hasAuth := (s.wectx.Token() != ""); hasAuth {
-		return nil
+		return s.checkTokenExpiry()
 	}
 
 	metrics.Rec(s.wectx.Config(), metrics.Event{
@@ -542,6 +549,55 @@ func (s *SetupHost) verifyCmdReqAuth() error {
 	return s.authenticateOrCancel()
 }
 
+// checkTokenExpiry fails before any request is made with an expired token,
+// and warns when the token is about to expire
+func (s *SetupHost) checkTokenExpiry() error {
+	if s.expiryChecked {
+		return nil
+	}
+
+	s.expiryChecked = true
+
+	var t, err = usertoken.ParseUnsignedJSONWebToken(s.wectx.Token())
+
+	if err != nil {
+		verbose.Debug("Skipping token expiry verification:", err)
+		return nil
+	}
+
+	warning, err := s.wectx.Config().SessionExpiryWarning()
+
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgHiRed, "Warning: %v", err))
+	}
+
+	remaining, warn, err := t.Check(time.Now(), warning)
+
+	switch {
+	case err == usertoken.ErrExpired:
+		return fmt.Errorf(`your session on %s expired %s ago: run "%s" to log in again`,
+			s.wectx.InfrastructureDomain(),
+			usertoken.FormatLifetime(remaining),
+			s.loginCommand())
+	case warn:
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgHiYellow,
+			`Warning: your session on %s expires in %s. Run "%s" to renew it.`,
+			s.wectx.InfrastructureDomain(),
+			usertoken.FormatLifetime(remaining),
+			s.loginCommand()))
+	}
+
+	return nil
+}
+
+func (s *SetupHost) loginCommand() string {
+	if s.wectx.Remote() == s.wectx.Config().GetParams().DefaultRemote {
+		return "lcp login"
+	}
+
+	return "lcp login --remote " + s.wectx.Remote()
+}
+
 func (s *SetupHost) authenticateOrCancel() error {
 	fmt.Printf("You need to log in before using \"%s\".\n",
 		strings.TrimSuffix(s.cmd.UseLine(), " [flags]"))
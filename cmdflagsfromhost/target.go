@@ -0,0 +1,30 @@
+package cmdflagsfromhost
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServiceTarget is a service given as a <project/service> argument or flag value
+type ServiceTarget struct {
+	Project string
+	Service string
+}
+
+func (t ServiceTarget) String() string {
+	return t.Project + "/" + t.Service
+}
+
+// ParseServiceTarget parses a <project/service> value
+func ParseServiceTarget(s string) (t ServiceTarget, err error) {
+	var parts = strings.Split(s, "/")
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return t, fmt.Errorf(`invalid service "%s": use <project/service> (e.g., acme-uat/liferay)`, s)
+	}
+
+	return ServiceTarget{
+		Project: parts[0],
+		Service: parts[1],
+	}, nil
+}
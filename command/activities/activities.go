@@ -2,17 +2,36 @@ package activities
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/ctxsignal"
 	"github.com/henvic/wedeploycli/activities"
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	cmdactivitiesreport "github.com/henvic/wedeploycli/command/activities/report"
 	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/errorhandler"
+	"github.com/henvic/wedeploycli/templates"
+	"github.com/henvic/wedeploycli/timehelper"
 	"github.com/spf13/cobra"
 )
 
 // ActivitiesCmd is the command to list activities on a deploymnet.
 var ActivitiesCmd = &cobra.Command{
-	Use:     "activities",
-	Short:   "List activities of a recent deployment",
+	Use:   "activities",
+	Short: "List activities of a recent deployment",
+	Example: `  lcp activities --project acme-prd --type CUSTOM_DOMAIN_UPDATED --since 2019-09-01 --until 2019-09-30
+  lcp activities --project acme-prd --service liferay --type DEPLOY_FAILED --type DEPLOY_SUCCEEDED --limit 10
+  lcp activities --project acme-prd --output json
+  lcp activities --project acme-prd --format "{{.CreatedAt}} {{.Type}} {{.Metadata.serviceId}}"
+  lcp activities --project acme-uat,acme-prd --watch
+  lcp activities --project acme-prd --watch --type DEPLOY_FAILED --exec ./page-on-call.sh`,
 	Args:    cobra.NoArgs,
 	PreRunE: preRun,
 	RunE:    activitiesRun,
@@ -22,9 +41,38 @@ var ActivitiesCmd = &cobra.Command{
 var (
 	commit   string
 	groupUID string
+	since    string
+	until    string
+	types    []string
+	service  string
+	limit    int
+	output   string
+	format   string
+	watch    bool
+	execHook string
 )
 
 func preRun(cmd *cobra.Command, args []string) error {
+	if output != "" && output != "json" {
+		return fmt.Errorf(`unsupported output "%s": use --output json or --format`, output)
+	}
+
+	if output != "" && format != "" {
+		return errors.New("incompatible use: --output and --format cannot be used together")
+	}
+
+	if limit < 0 {
+		return errors.New("limit must be a positive number")
+	}
+
+	if execHook != "" && !watch {
+		return errors.New("incompatible use: --exec requires --watch")
+	}
+
+	if watch && (limit != 0 || until != "") {
+		return errors.New("incompatible use: --limit and --until cannot be used with --watch")
+	}
+
 	return setupHost.Process(context.Background(), we.Context())
 }
 
@@ -41,25 +89,205 @@ var setupHost = cmdflagsfromhost.SetupHost{
 
 func init() {
 	setupHost.Init(ActivitiesCmd)
+	ActivitiesCmd.AddCommand(cmdactivitiesreport.Cmd)
 	ActivitiesCmd.Flags().StringVar(&commit, "commit", "", "Filter by deployment hash")
 	ActivitiesCmd.Flags().StringVar(&groupUID, "group", "", "Filter by Group UID")
+	ActivitiesCmd.Flags().StringVar(&since, "since", "", "Show since moment (i.e., 20min, 3h, 30d, 2019-09-01, UNIX timestamp)")
+	ActivitiesCmd.Flags().StringVar(&until, "until", "", "Show until moment (i.e., 20min, 3h, 30d, 2019-09-30, UNIX timestamp)")
+	ActivitiesCmd.Flags().StringArrayVar(&types, "type", nil, "Filter by activity type (i.e., DEPLOY_FAILED)")
+	ActivitiesCmd.Flags().StringVar(&service, "service", "", "Filter by service")
+	ActivitiesCmd.Flags().IntVar(&limit, "limit", 0, "Show only the most recent activities")
+	ActivitiesCmd.Flags().StringVarP(&output, "output", "o", "", "Output format (json)")
+	ActivitiesCmd.Flags().StringVarP(&format, "format", "f", "", "Format the output using the given go template")
+	ActivitiesCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for new activities")
+	ActivitiesCmd.Flags().StringVar(&execHook, "exec", "", "Run command for each new activity (activity JSON is sent on stdin)")
 }
 
 func activitiesRun(cmd *cobra.Command, args []string) (err error) {
-	activitiesClient := activities.New(we.Context())
+	var f activities.Filter
+
+	if f, err = getFilter(); err != nil {
+		return err
+	}
+
+	var projectIDs = getProjects()
 
-	var as []activities.Activity
-	var f = activities.Filter{
-		Commit:   commit,
-		GroupUID: groupUID,
+	if watch {
+		return watchRun(projectIDs, f)
 	}
 
-	as, err = activitiesClient.List(context.Background(), setupHost.Project(), f)
+	as, err := list(projectIDs, f)
 
 	if err != nil {
 		return err
 	}
 
+	switch {
+	case output == "json":
+		return printJSON(as)
+	case format != "":
+		return printFormat(as)
+	}
+
 	activities.PrettyPrintList(as)
 	return nil
 }
+
+func getProjects() []string {
+	var ids = []string{}
+
+	for _, p := range strings.Split(setupHost.Project(), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			ids = append(ids, p)
+		}
+	}
+
+	return ids
+}
+
+func list(projectIDs []string, f activities.Filter) ([]activities.Activity, error) {
+	activitiesClient := activities.New(we.Context())
+
+	var as = []activities.Activity{}
+
+	for _, p := range projectIDs {
+		l, err := activitiesClient.List(context.Background(), p, f)
+
+		if activities.IsLimitReached(err) {
+			warnLimitReached(p, err)
+			err = nil
+		}
+
+		if err != nil && len(projectIDs) == 1 {
+			return nil, err
+		}
+
+		if err != nil {
+			return nil, errwrap.Wrapf("can't list activities for project "+p+": {{err}}", err)
+		}
+
+		as = append(as, l...)
+	}
+
+	sort.SliceStable(as, func(i, j int) bool {
+		return as[i].CreatedAt < as[j].CreatedAt
+	})
+
+	return as, nil
+}
+
+func warnLimitReached(projectID string, err error) {
+	_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgYellow,
+		"Warning: %v on project %s (use --since or --until to narrow it down).", err, projectID))
+}
+
+func watchRun(projectIDs []string, f activities.Filter) error {
+	ctx, cancel := ctxsignal.WithTermination(context.Background())
+	defer cancel()
+
+	var w = &activities.Watcher{
+		Projects: projectIDs,
+		Filter:   f,
+		Handler: func(a activities.Activity) error {
+			if err := printActivity(a); err != nil {
+				return err
+			}
+
+			if execHook == "" {
+				return nil
+			}
+
+			return runHook(ctx, execHook, a)
+		},
+		ErrorHandler: func(err error) {
+			_, _ = fmt.Fprintf(os.Stderr, "%v\n", errorhandler.Handle(err))
+		},
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Watching for new activities on %s (press Ctrl+C to stop).\n",
+		strings.Join(projectIDs, ", "))
+
+	w.Watch(ctx, we.Context())
+	return nil
+}
+
+func printActivity(a activities.Activity) error {
+	switch {
+	case output == "json":
+		s, err := templates.Execute("{{json .}}", a)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(s)
+		return nil
+	case format != "":
+		return printFormat([]activities.Activity{a})
+	}
+
+	activities.PrettyPrintList([]activities.Activity{a})
+	return nil
+}
+
+func getFilter() (f activities.Filter, err error) {
+	f = activities.Filter{
+		Commit:    commit,
+		GroupUID:  groupUID,
+		Limit:     limit,
+		ServiceID: service,
+	}
+
+	switch len(types) {
+	case 0:
+	case 1:
+		f.Type = types[0]
+	default:
+		f.Types = types
+	}
+
+	var now = time.Now()
+
+	if since != "" {
+		if f.Since, err = timehelper.ParseMoment(since, now); err != nil {
+			return f, errwrap.Wrapf("can't parse since argument: {{err}}", err)
+		}
+	}
+
+	if until != "" {
+		if f.Until, err = timehelper.ParseEndMoment(until, now); err != nil {
+			return f, errwrap.Wrapf("can't parse until argument: {{err}}", err)
+		}
+	}
+
+	return f, nil
+}
+
+func printJSON(as []activities.Activity) error {
+	if as == nil {
+		as = []activities.Activity{}
+	}
+
+	var s, err = templates.ExecuteOrList("", as)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(s)
+	return nil
+}
+
+func printFormat(as []activities.Activity) error {
+	for _, a := range as {
+		var s, err = templates.Execute(format, a)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(s)
+	}
+
+	return nil
+}
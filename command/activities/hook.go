@@ -0,0 +1,58 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/activities"
+	"github.com/henvic/wedeploycli/verbose"
+)
+
+// Environment variables available to the --exec hook.
+const (
+	hookActivityID   = "LCP_ACTIVITY_ID"
+	hookActivityType = "LCP_ACTIVITY_TYPE"
+	hookProjectID    = "LCP_PROJECT_ID"
+	hookServiceID    = "LCP_SERVICE_ID"
+	hookCommit       = "LCP_COMMIT"
+	hookGroupUID     = "LCP_GROUP_UID"
+)
+
+func getHookEnv(a activities.Activity) []string {
+	var groupUID, _ = a.Metadata["groupUid"].(string)
+
+	return []string{
+		hookActivityID + "=" + a.ID,
+		hookActivityType + "=" + a.Type,
+		hookProjectID + "=" + a.ProjectID,
+		hookServiceID + "=" + a.ServiceID(),
+		hookCommit + "=" + a.Commit,
+		hookGroupUID + "=" + groupUID,
+	}
+}
+
+func runHook(ctx context.Context, command string, a activities.Activity) error {
+	var b, err = json.Marshal(a)
+
+	if err != nil {
+		return errwrap.Wrapf("can't encode activity: {{err}}", err)
+	}
+
+	var cmd = shellCommand(ctx, command)
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), getHookEnv(a)...)
+
+	verbose.Debug(fmt.Sprintf("Running hook for activity %s (%s)", a.ID, a.Type))
+
+	if err := cmd.Run(); err != nil {
+		return errwrap.Wrapf(fmt.Sprintf("hook for activity %s (%s) failed: {{err}}", a.ID, a.Type), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+// +build windows
+
+package activities
+
+import (
+	"context"
+	"os/exec"
+)
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec
+}
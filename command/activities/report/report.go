@@ -0,0 +1,98 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/activities"
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/timehelper"
+	"github.com/spf13/cobra"
+)
+
+var (
+	since  string
+	until  string
+	format string
+)
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.ProjectAndRemotePattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Project: true,
+		Auth:    true,
+	},
+
+	PromptMissingProject: true,
+}
+
+// Cmd for exporting an activities report
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "Export activities report grouped by type, actor, and service",
+	Example: `  lcp activities report --project acme-prd --since 30d --format csv > activities.csv
+  lcp activities report --project acme-prd --since 2019-09-01 --until 2019-10-01 --format md`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+func init() {
+	setupHost.Init(Cmd)
+
+	Cmd.Flags().StringVar(&since, "since", "30d", "Report since moment (i.e., 3h, 30d, 2019-09-01, UNIX timestamp)")
+	Cmd.Flags().StringVar(&until, "until", "", "Report until moment (i.e., 3h, 30d, 2019-09-30, UNIX timestamp)")
+	Cmd.Flags().StringVarP(&format, "format", "f", "md", "Report format (csv, md)")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if format != "csv" && format != "md" {
+		return fmt.Errorf(`unsupported format "%s": use csv or md`, format)
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	var now = time.Now()
+	var f activities.Filter
+
+	if f.Since, err = timehelper.ParseMoment(since, now); err != nil {
+		return errwrap.Wrapf("can't parse since argument: {{err}}", err)
+	}
+
+	f.Until = now
+
+	if until != "" {
+		if f.Until, err = timehelper.ParseEndMoment(until, now); err != nil {
+			return errwrap.Wrapf("can't parse until argument: {{err}}", err)
+		}
+	}
+
+	var activitiesClient = activities.New(we.Context())
+	as, err := activitiesClient.List(context.Background(), setupHost.Project(), f)
+
+	if activities.IsLimitReached(err) {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgYellow,
+			"Warning: %v (use --since or --until to narrow it down).", err))
+		err = nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var r = activities.NewReport(as, f.Since, f.Until)
+
+	if format == "csv" {
+		return r.CSV(os.Stdout)
+	}
+
+	return r.Markdown(os.Stdout)
+}
@@ -0,0 +1,174 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/canceled"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/drift"
+	"github.com/henvic/wedeploycli/envmask"
+	"github.com/henvic/wedeploycli/fancy"
+	"github.com/henvic/wedeploycli/isterm"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	plan        bool
+	autoApprove bool
+	strictEnv   bool
+	reveal      bool
+)
+
+// ApplyCmd applies the configuration of LCP.json files to live services
+var ApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply LCP.json configuration to services without deploying",
+	Long: `Apply LCP.json configuration to services without deploying
+
+Reconciles the scale, custom domains, and environment variables of the live services
+of a project with the LCP.json files of the current directory, without triggering a build.
+Changes to the image, cpu, or memory require a deployment and are only listed.
+Environment variables only defined remotely are kept, unless --strict-env is used
+and LCP.json has an "env" object.
+
+A plan is shown and confirmation is required before applying, unless --auto-approve is used.`,
+	Example: `  lcp apply --project acme-prd --plan
+  lcp apply --project acme-prd
+  lcp apply --project acme-prd --service web --auto-approve`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+	},
+
+	PromptMissingProject: true,
+}
+
+func init() {
+	setupHost.Init(ApplyCmd)
+	ApplyCmd.Flags().BoolVar(&plan, "plan", false, "Show the plan without applying it")
+	ApplyCmd.Flags().BoolVar(&autoApprove, "auto-approve", false, "Apply without asking for confirmation")
+	ApplyCmd.Flags().BoolVar(&strictEnv, "strict-env", false, "Remove environment variables not defined on LCP.json")
+	ApplyCmd.Flags().BoolVar(&reveal, "reveal", false, "Reveal masked secret values on the plan")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if plan && autoApprove {
+		return errors.New("incompatible use: --plan and --auto-approve cannot be used together")
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var ctx = context.Background()
+	var pkgs, err = drift.LocalPackages(".", setupHost.Service())
+
+	if err != nil {
+		return err
+	}
+
+	var wectx = we.Context()
+
+	var c = drift.Checker{
+		Client: services.New(wectx),
+		Options: drift.Options{
+			StrictEnv: strictEnv,
+		},
+	}
+
+	list, err := c.Check(ctx, setupHost.Project(), pkgs)
+
+	if err != nil {
+		return err
+	}
+
+	var changes = printPlan(list)
+
+	if len(changes) == 0 {
+		fmt.Println("No changes to apply.")
+		return nil
+	}
+
+	if plan {
+		return nil
+	}
+
+	if err := confirm(len(changes)); err != nil {
+		return err
+	}
+
+	for _, s := range changes {
+		if err := c.Apply(ctx, setupHost.Project(), s); err != nil {
+			return err
+		}
+
+		fmt.Printf("Applied changes to %s.\n", color.Format(color.Bold, s.ServiceID))
+	}
+
+	return nil
+}
+
+func printPlan(list []drift.Service) (changes []drift.Service) {
+	var wectx = we.Context()
+	var params = wectx.Config().GetParams()
+	var m = envmask.New(params.MaskEnvPatterns, params.MaskAllEnvs)
+	m.Reveal = reveal
+
+	for _, s := range list {
+		if !s.Drifted() {
+			continue
+		}
+
+		drift.Fprint(os.Stdout, s, m)
+
+		switch fields := s.RequiresDeploy(); {
+		case s.Missing:
+			fmt.Println(color.Format(color.FgHiBlack, "    skipped: deploy the service to create it"))
+		case len(fields) != 0:
+			fmt.Println(color.Format(color.FgHiBlack,
+				"    skipped: %s requires a deployment", strings.Join(fields, ", ")))
+		}
+
+		if s.Applicable() {
+			changes = append(changes, s)
+		}
+	}
+
+	return changes
+}
+
+func confirm(n int) error {
+	if autoApprove {
+		return nil
+	}
+
+	if !isterm.Check() {
+		return errors.New("can't ask for confirmation: use --auto-approve to apply without confirmation")
+	}
+
+	var q = fmt.Sprintf("Apply changes to %d services on project %s?", n, setupHost.Project())
+
+	switch ok, err := fancy.Boolean(q); {
+	case err != nil:
+		return err
+	case !ok:
+		return canceled.CancelCommand("apply canceled")
+	}
+
+	return nil
+}
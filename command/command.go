@@ -21,6 +21,7 @@ import (
 	"github.com/henvic/wedeploycli/command/internal/we"
 	"github.com/henvic/wedeploycli/command/root"
 	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/envmask"
 	"github.com/henvic/wedeploycli/envs"
 	"github.com/henvic/wedeploycli/errorhandler"
 	"github.com/henvic/wedeploycli/exiterror"
@@ -32,6 +33,7 @@ import (
 	"github.com/henvic/wedeploycli/update"
 	"github.com/henvic/wedeploycli/userhome"
 	"github.com/henvic/wedeploycli/verbose"
+	"github.com/henvic/wedeploycli/verbosereq"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -284,6 +286,8 @@ func (cl *configLoader) loadConfig() {
 		color.NoColor = true
 		conf.SetParams(params)
 	}
+
+	verbosereq.EnvMasker = envmask.New(params.MaskEnvPatterns, params.MaskAllEnvs)
 }
 
 func (cl *configLoader) checkPastVersion() {
@@ -292,7 +296,8 @@ func (cl *configLoader) checkPastVersion() {
 	var params = conf.GetParams()
 
 	if params.PastVersion != "" {
-		update.ApplyTransitions(params.PastVersion)
+		update.ApplyTransitions(conf, params.PastVersion)
+		params = conf.GetParams()
 		params.PastVersion = ""
 		conf.SetParams(params)
 		cl.reload = true
@@ -0,0 +1,106 @@
+package cp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/shell"
+	"github.com/spf13/cobra"
+)
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern | cmdflagsfromhost.InstancePattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:     true,
+		Project:  true,
+		Service:  true,
+		Instance: true,
+	},
+
+	AutoSelectSingleInstance: true,
+
+	PromptMissingService:  true,
+	PromptMissingInstance: true,
+}
+
+// CpCmd copies files to and from service instances
+var CpCmd = &cobra.Command{
+	Use:   "cp <source> <destination directory>",
+	Short: "Copy files to and from instances of your service",
+	Long: `Copy files to and from instances of your service
+
+Remote paths are prefixed with ":". Files and directories are copied into the
+destination directory, which is created if it doesn't exist.`,
+	Example: `  lcp cp -p acme-prd -s liferay ./hotfix.jar :/opt/liferay/deploy
+  lcp cp -p acme-prd -s liferay --instance ab123 :/opt/liferay/heap.hprof .`,
+	Args:    cobra.ExactArgs(2),
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+func init() {
+	setupHost.Init(CpCmd)
+}
+
+func remotePath(s string) (string, bool) {
+	if !strings.HasPrefix(s, ":") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(s, ":"), true
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	var _, srcRemote = remotePath(args[0])
+	var _, dstRemote = remotePath(args[1])
+
+	if srcRemote == dstRemote {
+		return errors.New(`either the source or the destination must be a remote path prefixed with ":"`)
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var wectx = we.Context()
+	var host = wectx.Infrastructure()
+
+	host = strings.Replace(host, "http://", "", 1)
+	host = strings.Replace(host, "https://", "", 1)
+
+	var params = shell.Params{
+		Host:  host,
+		Token: wectx.Token(),
+
+		ProjectID: setupHost.Project(),
+		ServiceID: setupHost.Service(),
+		Instance:  setupHost.Instance(),
+	}
+
+	var ctx = context.Background()
+
+	if src, ok := remotePath(args[0]); ok {
+		if err := shell.Download(ctx, params, src, args[1]); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Copied %s to %s.\n", color.Format(color.Bold, args[0]), args[1])
+		return nil
+	}
+
+	var dst, _ = remotePath(args[1])
+
+	if err := shell.Upload(ctx, params, args[0], dst); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Copied %s to %s.\n", args[0], color.Format(color.Bold, args[1]))
+	return nil
+}
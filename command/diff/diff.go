@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/drift"
+	"github.com/henvic/wedeploycli/envmask"
+	"github.com/henvic/wedeploycli/exiterror"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	strictEnv bool
+	reveal    bool
+)
+
+// DriftExitCode is used when configuration drift is found
+const DriftExitCode = 2
+
+// DiffCmd compares local LCP.json files with the live services
+var DiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Detect configuration drift between LCP.json and live services",
+	Long: `Detect configuration drift between LCP.json and live services
+
+Compares the image, scale, cpu, memory, custom domains, and environment variables defined
+on the LCP.json files of the current directory with the live services of the project.
+Fields not defined locally are ignored. Environment variables only defined remotely
+are ignored, unless --strict-env is used and LCP.json has an "env" object.
+
+Exits with status code 2 if drift is found, so it can be used to gate pipelines.`,
+	Example: `  lcp diff --project acme-prd
+  lcp diff --project acme-prd --service web
+  lcp diff --strict-env`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+	},
+
+	PromptMissingProject: true,
+}
+
+func init() {
+	setupHost.Init(DiffCmd)
+	DiffCmd.Flags().BoolVar(&strictEnv, "strict-env", false, "Consider environment variables only defined remotely as drift")
+	DiffCmd.Flags().BoolVar(&reveal, "reveal", false, "Reveal masked secret values")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var pkgs, err = drift.LocalPackages(".", setupHost.Service())
+
+	if err != nil {
+		return err
+	}
+
+	var wectx = we.Context()
+
+	var c = drift.Checker{
+		Client: services.New(wectx),
+		Options: drift.Options{
+			StrictEnv: strictEnv,
+		},
+	}
+
+	list, err := c.Check(context.Background(), setupHost.Project(), pkgs)
+
+	if err != nil {
+		return err
+	}
+
+	var params = wectx.Config().GetParams()
+	var m = envmask.New(params.MaskEnvPatterns, params.MaskAllEnvs)
+	m.Reveal = reveal
+
+	var drifted int
+
+	for _, s := range list {
+		drift.Fprint(os.Stdout, s, m)
+
+		if s.Drifted() {
+			drifted++
+		}
+	}
+
+	if drifted == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "No drift found.")
+		return nil
+	}
+
+	return exiterror.New(fmt.Sprintf("configuration drift found on %d of %d services", drifted, len(list)), DriftExitCode)
+}
@@ -0,0 +1,97 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/domaincheck"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dnsServer string
+	timeout   time.Duration
+)
+
+// Cmd for checking domains
+var Cmd = &cobra.Command{
+	Use:   "check [domain...]",
+	Short: "Check DNS records and TLS certificates of custom domains",
+	Long: `Check DNS records and TLS certificates of custom domains
+
+Each custom domain is resolved and compared with the service endpoint.
+The TLS certificate served for the domain is verified, and a warning is shown
+when it is about to expire.`,
+	Example: `  lcp domain check
+  lcp domain check example.com --dns-server 8.8.8.8`,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+		Service: true,
+	},
+
+	PromptMissingService: true,
+}
+
+func init() {
+	setupHost.Init(Cmd)
+	Cmd.Flags().StringVar(&dnsServer, "dns-server", "", "DNS server to use instead of the local resolver (host[:port])")
+	Cmd.Flags().DurationVar(&timeout, "timeout", domaincheck.DefaultTimeout, "Timeout for each DNS lookup and TLS connection")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var ctx = context.Background()
+	var domains = args
+
+	if len(domains) == 0 {
+		var service, err = services.New(we.Context()).Get(ctx, setupHost.Project(), setupHost.Service())
+
+		if err != nil {
+			return err
+		}
+
+		domains = service.CustomDomains
+	}
+
+	if len(domains) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "No custom domains found.\n")
+		return nil
+	}
+
+	var c = &domaincheck.Checker{
+		DNSServer: dnsServer,
+		Timeout:   timeout,
+	}
+
+	var failed int
+
+	for _, r := range c.Check(ctx, setupHost.Host(), domains) {
+		domaincheck.Fprint(os.Stdout, r)
+
+		if !r.OK() {
+			failed++
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("problems found on %d of %d domains", failed, len(domains))
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	cmddomainadd "github.com/henvic/wedeploycli/command/domain/add"
+	cmddomaincheck "github.com/henvic/wedeploycli/command/domain/check"
 	"github.com/henvic/wedeploycli/command/domain/internal/commands"
 	cmddomainremove "github.com/henvic/wedeploycli/command/domain/remove"
 	cmddomainshow "github.com/henvic/wedeploycli/command/domain/show"
@@ -27,7 +28,8 @@ var DomainCmd = &cobra.Command{
 	Short: "Show and configure domain names for services",
 	Example: `  lcp domain (to list domains)
   lcp domain add foo.com
-  lcp domain rm foo.com`,
+  lcp domain rm foo.com
+  lcp domain check`,
 	Args:    cobra.NoArgs,
 	PreRunE: idc.preRun,
 	RunE:    idc.run,
@@ -109,4 +111,5 @@ func init() {
 	DomainCmd.AddCommand(cmddomainshow.Cmd)
 	DomainCmd.AddCommand(cmddomainadd.Cmd)
 	DomainCmd.AddCommand(cmddomainremove.Cmd)
+	DomainCmd.AddCommand(cmddomaincheck.Cmd)
 }
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/envmask"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var reveal bool
+
+// Cmd for comparing environment variables
+var Cmd = &cobra.Command{
+	Use:   "diff <project/service> <project/service>",
+	Short: "Compare environment variables between two services",
+	Example: `  lcp env-var diff acme-uat/liferay acme-prd/liferay
+  lcp env-var diff acme-uat/liferay acme-prd/liferay --reveal`,
+	Args:    cobra.ExactArgs(2),
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.RemotePattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		NoHost: true,
+		Auth:   true,
+	},
+}
+
+func init() {
+	setupHost.Init(Cmd)
+	Cmd.Flags().BoolVar(&reveal, "reveal", false, "Show values instead of masking them")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	for _, a := range args {
+		if _, err := cmdflagsfromhost.ParseServiceTarget(a); err != nil {
+			return err
+		}
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var left, _ = cmdflagsfromhost.ParseServiceTarget(args[0])
+	var right, _ = cmdflagsfromhost.ParseServiceTarget(args[1])
+
+	var servicesClient = services.New(we.Context())
+	var ctx = context.Background()
+
+	leftEnvs, err := servicesClient.GetEnvironmentVariables(ctx, left.Project, left.Service)
+
+	if err != nil {
+		return errwrap.Wrapf("can't get environment variables for "+left.String()+": {{err}}", err)
+	}
+
+	rightEnvs, err := servicesClient.GetEnvironmentVariables(ctx, right.Project, right.Service)
+
+	if err != nil {
+		return errwrap.Wrapf("can't get environment variables for "+right.String()+": {{err}}", err)
+	}
+
+	var changes = envfile.Diff(leftEnvs, rightEnvs)
+
+	if changes.Empty() {
+		_, _ = fmt.Fprintf(os.Stderr, "No differences found between %s and %s.\n", left, right)
+		return nil
+	}
+
+	printDiff(os.Stdout, left, right, changes, envmask.Masker{
+		All:    true,
+		Reveal: reveal,
+	})
+
+	return nil
+}
+
+func printDiff(w io.Writer, left, right cmdflagsfromhost.ServiceTarget, changes envfile.Changes, m envmask.Masker) {
+	// from left to right: removed keys exist only on the left, added keys only on the right
+	if len(changes.Removed) != 0 {
+		_, _ = fmt.Fprintln(w, color.Format(color.FgHiBlack, "Only in "+left.String()+":"))
+
+		for _, e := range changes.Removed {
+			_, _ = fmt.Fprintf(w, "%s %s=%s\n", color.Format(color.FgRed, "<"), e.Name, m.Mask(e.Name, e.Value))
+		}
+	}
+
+	if len(changes.Added) != 0 {
+		_, _ = fmt.Fprintln(w, color.Format(color.FgHiBlack, "Only in "+right.String()+":"))
+
+		for _, e := range changes.Added {
+			_, _ = fmt.Fprintf(w, "%s %s=%s\n", color.Format(color.FgGreen, ">"), e.Name, m.Mask(e.Name, e.Value))
+		}
+	}
+
+	if len(changes.Changed) != 0 {
+		_, _ = fmt.Fprintln(w, color.Format(color.FgHiBlack, "Changed:"))
+
+		for _, e := range changes.Changed {
+			_, _ = fmt.Fprintf(w, "%s %s=%s %s %s\n",
+				color.Format(color.FgYellow, "~"),
+				e.Name,
+				m.Mask(e.Name, e.From),
+				color.Format(color.FgHiBlack, "=>"),
+				m.Mask(e.Name, e.To))
+		}
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	cmdenvdiff "github.com/henvic/wedeploycli/command/env-var/diff"
+	cmdenvexport "github.com/henvic/wedeploycli/command/env-var/export"
+	cmdenvimport "github.com/henvic/wedeploycli/command/env-var/import"
 	"github.com/henvic/wedeploycli/command/env-var/internal/commands"
 	cmdenvset "github.com/henvic/wedeploycli/command/env-var/set"
 	cmdenvshow "github.com/henvic/wedeploycli/command/env-var/show"
+	cmdenvsync "github.com/henvic/wedeploycli/command/env-var/sync"
 	cmdenvunset "github.com/henvic/wedeploycli/command/env-var/unset"
 	"github.com/henvic/wedeploycli/command/internal/we"
 	"github.com/henvic/wedeploycli/fancy"
@@ -109,4 +113,8 @@ func init() {
 	EnvCmd.AddCommand(cmdenvshow.Cmd)
 	EnvCmd.AddCommand(cmdenvset.Cmd)
 	EnvCmd.AddCommand(cmdenvunset.Cmd)
+	EnvCmd.AddCommand(cmdenvexport.Cmd)
+	EnvCmd.AddCommand(cmdenvimport.Cmd)
+	EnvCmd.AddCommand(cmdenvdiff.Cmd)
+	EnvCmd.AddCommand(cmdenvsync.Cmd)
 }
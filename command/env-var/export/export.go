@@ -0,0 +1,62 @@
+package export
+
+import (
+	"context"
+	"os"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/command/env-var/internal/commands"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var format string
+
+// Cmd for exporting environment variables
+var Cmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export environment variables of a given service",
+	Example: `  lcp env-var export > .env
+  lcp env-var export --format json > env.json
+  lcp env-var export --format yaml > env.yaml`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+		Service: true,
+	},
+
+	PromptMissingService: true,
+}
+
+func init() {
+	setupHost.Init(Cmd)
+	Cmd.Flags().StringVarP(&format, "format", "f", string(envfile.Dotenv), "Output format (dotenv, json, yaml)")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if _, err := envfile.ParseFormat(format); err != nil {
+		return err
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var c = commands.Command{
+		SetupHost:      setupHost,
+		ServicesClient: services.New(we.Context()),
+	}
+
+	var f, _ = envfile.ParseFormat(format)
+	return c.Export(context.Background(), os.Stdout, f)
+}
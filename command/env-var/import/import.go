@@ -0,0 +1,89 @@
+package envimport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/command/env-var/internal/commands"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	format  string
+	replace bool
+	merge   bool
+	dryRun  bool
+	reveal  bool
+)
+
+// Cmd for importing environment variables
+var Cmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import environment variables from a file",
+	Long: `Import environment variables from a file
+
+The file format is guessed from the file extension (.json, .yaml, .yml, or dotenv otherwise).
+Dotenv files support comments, single and double quoted values, and multi-line quoted values.
+By default, the imported variables are merged with the existing ones.`,
+	Example: `  lcp env-var import prd.env
+  lcp env-var import prd.env --replace
+  lcp env-var import env.json --dry-run`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+		Service: true,
+	},
+
+	PromptMissingService: true,
+}
+
+func init() {
+	setupHost.Init(Cmd)
+	Cmd.Flags().StringVarP(&format, "format", "f", "", "File format (dotenv, json, yaml)")
+	Cmd.Flags().BoolVar(&replace, "replace", false, "Replace the set of environment variables (removing missing keys)")
+	Cmd.Flags().BoolVar(&merge, "merge", false, "Merge with existing environment variables (default)")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without applying them")
+	Cmd.Flags().BoolVar(&reveal, "reveal", false, "Reveal masked secret values on changes")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if replace && merge {
+		return errors.New("incompatible use: --replace and --merge cannot be used together")
+	}
+
+	if format != "" {
+		if _, err := envfile.ParseFormat(format); err != nil {
+			return err
+		}
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var envs, err = envfile.ReadFile(args[0], format)
+
+	if err != nil {
+		return err
+	}
+
+	var c = commands.Command{
+		SetupHost:      setupHost,
+		ServicesClient: services.New(we.Context()),
+		Reveal:         reveal,
+	}
+
+	return c.Import(context.Background(), envs, replace, dryRun)
+}
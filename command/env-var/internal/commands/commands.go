@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strconv"
@@ -12,7 +13,10 @@ import (
 	"github.com/hashicorp/errwrap"
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/canceled"
 	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/envmask"
 	"github.com/henvic/wedeploycli/fancy"
 	"github.com/henvic/wedeploycli/isterm"
 	"github.com/henvic/wedeploycli/list"
@@ -30,6 +34,17 @@ type Command struct {
 	Envs []services.EnvironmentVariable
 
 	SkipPrompt bool
+
+	// Reveal secret values instead of masking them
+	Reveal bool
+}
+
+func (c *Command) masker() envmask.Masker {
+	var wectx = we.Context()
+	var params = wectx.Config().GetParams()
+	var m = envmask.New(params.MaskEnvPatterns, params.MaskAllEnvs)
+	m.Reveal = c.Reveal
+	return m
 }
 
 func has(filterEnvKeys []string, key string) bool {
@@ -92,8 +107,10 @@ func (c *Command) Show(ctx context.Context, filterEnvKeys ...string) error {
 		color.Format(color.FgHiBlack, "Key"),
 		color.Format(color.FgHiBlack, "Value"))
 
+	var m = c.masker()
+
 	for c, v := range c.Envs {
-		fmt.Printf("%d\t%s\t%s\n", c+1, v.Name, v.Value)
+		fmt.Printf("%d\t%s\t%s\n", c+1, v.Name, m.Mask(v.Name, v.Value))
 	}
 
 	return nil
@@ -259,3 +276,82 @@ func filterEmptyEnvValues(envKeys []string) []string {
 
 	return filtered
 }
+
+// Export environment variables in a given format
+func (c *Command) Export(ctx context.Context, w io.Writer, f envfile.Format) error {
+	var envs, err = c.ServicesClient.GetEnvironmentVariables(ctx,
+		c.SetupHost.Project(),
+		c.SetupHost.Service())
+
+	if err != nil {
+		return err
+	}
+
+	return envfile.Encode(w, envs, f)
+}
+
+// Import environment variables, merging them with the existing ones or replacing them
+func (c *Command) Import(ctx context.Context, envs []services.EnvironmentVariable, replace, dryRun bool) error {
+	var current, err = c.ServicesClient.GetEnvironmentVariables(ctx,
+		c.SetupHost.Project(),
+		c.SetupHost.Service())
+
+	if err != nil {
+		return err
+	}
+
+	var next = envs
+
+	if !replace {
+		next = envfile.Merge(current, envs)
+	}
+
+	var changes = envfile.Diff(current, next)
+
+	if changes.Empty() {
+		_, _ = fmt.Fprintln(os.Stderr, "No environment variable changes.")
+		return nil
+	}
+
+	c.PrintChanges(os.Stdout, changes)
+
+	if dryRun {
+		return nil
+	}
+
+	if !c.SkipPrompt && isterm.Check() {
+		switch ok, askErr := fancy.Boolean("Apply changes to \"" + c.SetupHost.Host() + "\"?"); {
+		case askErr != nil:
+			return askErr
+		case !ok:
+			return canceled.CancelCommand("import canceled")
+		}
+	}
+
+	if err := c.ServicesClient.SetEnvironmentVariables(ctx, c.SetupHost.Project(), c.SetupHost.Service(), next); err != nil {
+		return err
+	}
+
+	fmt.Printf("Environment variables updated (%d added, %d changed, %d removed).\n",
+		len(changes.Added), len(changes.Changed), len(changes.Removed))
+	return nil
+}
+
+// PrintChanges of environment variables, masking secret values
+func (c *Command) PrintChanges(w io.Writer, changes envfile.Changes) {
+	var m = c.masker()
+
+	for _, e := range changes.Added {
+		_, _ = fmt.Fprintf(w, "%s %s=%s\n", color.Format(color.FgGreen, "+"), e.Name, m.Mask(e.Name, e.Value))
+	}
+
+	for _, e := range changes.Changed {
+		_, _ = fmt.Fprintf(w, "%s %s=%s %s %s\n",
+			color.Format(color.FgYellow, "~"), e.Name, m.Mask(e.Name, e.From),
+			color.Format(color.FgHiBlack, "=>"), m.Mask(e.Name, e.To))
+	}
+
+	for _, e := range changes.Removed {
+		_, _ = fmt.Fprintf(w, "%s %s\n", color.Format(color.FgRed, "-"), e.Name)
+	}
+}
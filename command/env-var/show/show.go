@@ -15,12 +15,19 @@ var Cmd = &cobra.Command{
 	Use:     "show",
 	Aliases: []string{"list"},
 	Short:   "Show your environment variable values for a given service",
+	Long: `Show your environment variable values for a given service
+
+Values of variables with names matching *PASSWORD*, *SECRET*, *TOKEN*, or *KEY* are masked.
+Use mask_env_patterns (comma-separated) or mask_all_env_values on the configuration file to change it.`,
 	Example: `  lcp env-var show
-  lcp env-var show key`,
+  lcp env-var show key
+  lcp env-var show --reveal`,
 	PreRunE: preRun,
 	RunE:    run,
 }
 
+var reveal bool
+
 var setupHost = cmdflagsfromhost.SetupHost{
 	Pattern: cmdflagsfromhost.FullHostPattern,
 
@@ -35,6 +42,7 @@ var setupHost = cmdflagsfromhost.SetupHost{
 
 func init() {
 	setupHost.Init(Cmd)
+	Cmd.Flags().BoolVar(&reveal, "reveal", false, "Reveal masked secret values")
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
@@ -45,6 +53,7 @@ func run(cmd *cobra.Command, args []string) error {
 	var c = commands.Command{
 		SetupHost:      setupHost,
 		ServicesClient: services.New(we.Context()),
+		Reveal:         reveal,
 	}
 
 	return c.Show(context.Background(), args...)
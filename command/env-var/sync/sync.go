@@ -0,0 +1,94 @@
+package envsync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/command/env-var/internal/commands"
+	"github.com/henvic/wedeploycli/command/internal/passphrase"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/secrets"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	file    string
+	replace bool
+	dryRun  bool
+	reveal  bool
+)
+
+// Cmd for syncing environment variables from the encrypted secrets file
+var Cmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Apply encrypted secrets as environment variables",
+	Long: `Apply encrypted secrets as environment variables
+
+The secrets file (LCP.secrets.json by default) is decrypted locally and its values
+are merged with the existing environment variables of the service.
+See "lcp secrets" to manage the secrets file.`,
+	Example: `  lcp env-var sync
+  lcp env-var sync --file prd/LCP.secrets.json --dry-run
+  lcp env-var sync --replace`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+		Service: true,
+	},
+
+	PromptMissingService: true,
+}
+
+func init() {
+	setupHost.Init(Cmd)
+	Cmd.Flags().StringVar(&file, "file", secrets.Filename, "Secrets file")
+	Cmd.Flags().BoolVar(&replace, "replace", false, "Replace the set of environment variables (removing missing keys)")
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without applying them")
+	Cmd.Flags().BoolVar(&reveal, "reveal", false, "Reveal masked secret values on changes")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if !secrets.Exists(file) {
+		return errors.New("secrets file " + file + " not found")
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var sf, err = secrets.Read(file)
+
+	if err != nil {
+		return err
+	}
+
+	p, err := passphrase.Get(false)
+
+	if err != nil {
+		return err
+	}
+
+	envs, err := sf.Decrypt(p)
+
+	if err != nil {
+		return err
+	}
+
+	var c = commands.Command{
+		SetupHost:      setupHost,
+		ServicesClient: services.New(we.Context()),
+		Reveal:         reveal,
+	}
+
+	return c.Import(context.Background(), envs, replace, dryRun)
+}
@@ -15,7 +15,7 @@ func MaybeRewrite(cmd *cobra.Command, args []string) ([]string, bool) {
 		return []string{}, false
 	}
 
-	stringFlags := getStringFlags(cmd.Flags())
+	valueFlags := getValueFlags(cmd.Flags())
 	skip := false
 
 	found := -1
@@ -32,7 +32,7 @@ func MaybeRewrite(cmd *cobra.Command, args []string) ([]string, bool) {
 
 		if strings.HasPrefix(a, "-") {
 			// remember flags might be --foo, --foo=value, and --foo value.
-			if stringFlags[a] {
+			if valueFlags[a] {
 				skip = true
 			}
 
@@ -57,7 +57,7 @@ func MaybeRewrite(cmd *cobra.Command, args []string) ([]string, bool) {
 func getPosition(cmd *cobra.Command, args []string) (int, bool) {
 	name := cmd.Name()
 
-	stringFlags := getStringFlags(cmd.Flags())
+	valueFlags := getValueFlags(cmd.Flags())
 	skip := false
 
 	for index, a := range args {
@@ -72,7 +72,7 @@ func getPosition(cmd *cobra.Command, args []string) (int, bool) {
 
 		if strings.HasPrefix(a, "-") {
 			// remember flags might be --foo, --foo=value, and --foo value.
-			if stringFlags[a] {
+			if valueFlags[a] {
 				skip = true
 			}
 
@@ -89,11 +89,13 @@ func getPosition(cmd *cobra.Command, args []string) (int, bool) {
 	return -1, false
 }
 
-func getStringFlags(all *pflag.FlagSet) map[string]bool {
+// getValueFlags returns the flags that take the next argument as value when used as --foo value.
+// Flags with a default value when used without one (such as bool and count flags) don't.
+func getValueFlags(all *pflag.FlagSet) map[string]bool {
 	var flags = map[string]bool{}
 
 	all.VisitAll(func(f *pflag.Flag) {
-		if f.Value.Type() != "string" {
+		if f.NoOptDefVal != "" {
 			return
 		}
 
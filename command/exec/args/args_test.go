@@ -0,0 +1,114 @@
+package execargs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newExecCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use: "exec",
+	}
+
+	var f = cmd.Flags()
+	f.StringP("project", "p", "", "")
+	f.StringP("service", "s", "", "")
+	f.StringP("url", "u", "", "")
+	f.String("instance", "", "")
+	f.Bool("all-instances", false, "")
+	f.Int("parallel", 4, "")
+	f.Duration("timeout", 0, "")
+	f.StringSlice("env", nil, "")
+	f.CountP("verbose", "v", "")
+	return cmd
+}
+
+func TestMaybeRewrite(t *testing.T) {
+	var cases = []struct {
+		args    []string
+		want    []string
+		rewrite bool
+	}{
+		{
+			args:    []string{"exec", "ls"},
+			want:    []string{"exec", "--", "ls"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "--project", "foo", "ls", "-la"},
+			want:    []string{"exec", "--project", "foo", "--", "ls", "-la"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "-p", "foo", "-s", "bar", "ls"},
+			want:    []string{"exec", "-p", "foo", "-s", "bar", "--", "ls"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "--project=foo", "--service", "bar", "ls"},
+			want:    []string{"exec", "--project=foo", "--service", "bar", "--", "ls"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "--url", "bar-foo.liferay.cloud", "--instance", "abc", "ls"},
+			want:    []string{"exec", "--url", "bar-foo.liferay.cloud", "--instance", "abc", "--", "ls"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"--project", "foo", "exec", "ls"},
+			want:    []string{"--project", "foo", "exec", "--", "ls"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "--parallel", "4", "cmd"},
+			want:    []string{"exec", "--parallel", "4", "--", "cmd"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "--all-instances", "--timeout", "10s", "cat", "/etc/hosts"},
+			want:    []string{"exec", "--all-instances", "--timeout", "10s", "--", "cat", "/etc/hosts"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "--env", "A=1", "env"},
+			want:    []string{"exec", "--env", "A=1", "--", "env"},
+			rewrite: true,
+		},
+		{
+			args:    []string{"exec", "-v", "ls"},
+			want:    []string{"exec", "-v", "--", "ls"},
+			rewrite: true,
+		},
+		{
+			args: []string{"exec", "--parallel", "4", "--", "cmd"},
+		},
+		{
+			args: []string{"exec", "--project", "foo", "--", "ls", "-la"},
+		},
+		{
+			args: []string{"exec", "--project", "foo"},
+		},
+		{
+			args: []string{"exec"},
+		},
+		{
+			args: []string{"list", "ls"},
+		},
+	}
+
+	for _, c := range cases {
+		var args = append([]string{}, c.args...)
+		var got, rewrite = MaybeRewrite(newExecCmd(), args)
+
+		if c.want == nil {
+			c.want = []string{}
+		}
+
+		if rewrite != c.rewrite || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Wanted %v (rewrite: %v) for %v, got %v (rewrite: %v) instead",
+				c.want, c.rewrite, c.args, got, rewrite)
+		}
+	}
+}
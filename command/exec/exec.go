@@ -2,12 +2,21 @@ package exec
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/errwrap"
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
 	"github.com/henvic/wedeploycli/verbose"
 
 	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/exiterror"
 	"github.com/henvic/wedeploycli/isterm"
 	"github.com/henvic/wedeploycli/services"
 	"github.com/henvic/wedeploycli/shell"
@@ -29,24 +38,100 @@ var setupHost = cmdflagsfromhost.SetupHost{
 	PromptMissingInstance: true,
 }
 
+var (
+	allInstances bool
+	concurrency  int
+	reconnect    bool
+	detach       bool
+	attach       string
+	script       string
+	interpreter  string
+)
+
 // ExecCmd executes a process (command) remotely
 var ExecCmd = &cobra.Command{
 	Use:   "exec",
 	Short: "Execute command remotely",
 	Example: `  lcp exec -p demo -s web -- ls
   lcp exec -p demo -s web --instance any -- uname -a (run command on any instance)
-  lcp exec -p demo -s web --instance ab123 -- backup-db`,
+  lcp exec -p demo -s web --instance ab123 -- backup-db
+  lcp exec -p demo -s web --instance ab123 --reconnect -- backup-db (follow output again if disconnected)
+  lcp exec -p demo -s web --all-instances -- cat /etc/nginx/nginx.conf
+  lcp exec -p demo -s web --instance ab123 --detach -- reindex.sh (run in the background)
+  lcp exec -p demo -s web --instance ab123 --attach 20191001-120000-3f9a2c1d (follow output of a detached command)
+  lcp exec -p demo -s web --script ./migrate.sh -- --dry-run (run local script)`,
 	PreRunE: execPreRun,
 	RunE:    execRun,
-	Args:    cobra.MinimumNArgs(1),
+	Args:    execArgs,
 	Hidden:  true,
 }
 
 func init() {
 	setupHost.Init(ExecCmd)
+	ExecCmd.Flags().BoolVar(&allInstances, "all-instances", false, "Run command on all instances of the service")
+	ExecCmd.Flags().IntVar(&concurrency, "parallel", shell.DefaultConcurrency, "Number of instances running the command at the same time")
+	ExecCmd.Flags().BoolVar(&reconnect, "reconnect", false, "Run command in the background and follow its output again if the connection is lost (stdin is not forwarded)")
+	ExecCmd.Flags().BoolVar(&detach, "detach", false, "Run command in the background with nohup, so it continues if disconnected")
+	ExecCmd.Flags().StringVar(&attach, "attach", "", "Follow the output of a detached command")
+	ExecCmd.Flags().StringVar(&script, "script", "", "Run a local script file, passing the arguments to it")
+	ExecCmd.Flags().StringVar(&interpreter, "interpreter", "",
+		"Interpreter for the script (default: its shebang line, or sh)")
+}
+
+func execArgs(cmd *cobra.Command, args []string) error {
+	switch {
+	case attach != "":
+		return cobra.NoArgs(cmd, args)
+	case script != "":
+		return cobra.ArbitraryArgs(cmd, args)
+	}
+
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
+func checkModes() error {
+	var modes int
+
+	for _, m := range []bool{allInstances, detach, attach != "", script != ""} {
+		if m {
+			modes++
+		}
+	}
+
+	if modes > 1 {
+		return errors.New("can't use --all-instances, --detach, --attach, and --script together")
+	}
+
+	if reconnect && modes != 0 {
+		return errors.New("--reconnect can't be used with --all-instances, --detach, --attach, or --script")
+	}
+
+	if interpreter != "" && script == "" {
+		return errors.New("--interpreter can only be used with --script")
+	}
+
+	if attach != "" {
+		return shell.ValidateJobID(attach)
+	}
+
+	return nil
 }
 
 func execPreRun(cmd *cobra.Command, args []string) error {
+	if err := checkModes(); err != nil {
+		return err
+	}
+
+	if allInstances {
+		if cmd.Flag("instance").Changed {
+			return errors.New("can't use --instance and --all-instances together")
+		}
+
+		setupHost.Requires.Instance = false
+		setupHost.AutoSelectSingleInstance = false
+		setupHost.PromptMissingInstance = false
+	}
+
 	if err := setupHost.Process(context.Background(), we.Context()); err != nil {
 		return err
 	}
@@ -82,12 +167,154 @@ func execRun(cmd *cobra.Command, args []string) error {
 		TTY:         isterm.Stdin(),
 	}
 
+	switch {
+	case script != "":
+		return execScript(params, args)
+	case allInstances:
+		return execAll(params, args[0], childArgs...)
+	case attach != "":
+		return shell.Attach(context.Background(), params, attach, newReconnect())
+	case detach:
+		return execDetach(params, args[0], childArgs...)
+	}
+
 	switch params.TTY {
 	case true:
 		verbose.Debug("Attaching tty")
+		return shell.Run(context.Background(), params, args[0], childArgs...)
 	default:
 		verbose.Debug("Not attaching tty")
 	}
 
-	return shell.Run(context.Background(), params, args[0], childArgs...)
+	if reconnect {
+		return execReconnect(params, args[0], childArgs...)
+	}
+
+	var instance, err = shell.RunInstance(context.Background(), params, args[0], childArgs...)
+
+	if shell.IsTransportError(err) && instance != "" {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgYellow,
+			"Connection lost. The command might still be running on instance %s. "+
+				"Use --reconnect or --detach to follow the output of long-running commands again when disconnected.",
+			instance))
+	}
+
+	return err
+}
+
+// execReconnect runs the command detached from the session, so reconnecting only follows its output again,
+// rather than running the command again
+func execReconnect(params shell.Params, cmd string, args ...string) error {
+	var job, err = shell.NewJobID(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	instance, err := shell.Detach(context.Background(), params, job, cmd, args...)
+
+	if err != nil {
+		return err
+	}
+
+	verbose.Debug(fmt.Sprintf("Started job %s on instance %s", job, instance))
+	params.Instance = instance
+	return shell.Attach(context.Background(), params, job, newReconnect())
+}
+
+func execScript(params shell.Params, args []string) error {
+	content, err := ioutil.ReadFile(script)
+
+	if err != nil {
+		return errwrap.Wrapf("can't read script: {{err}}", err)
+	}
+
+	var i = interpreter
+
+	if i == "" {
+		i = shell.Interpreter(content)
+	}
+
+	return shell.RunScript(context.Background(), params, content, i, args...)
+}
+
+func newReconnect() shell.Reconnect {
+	var r = shell.DefaultReconnect
+
+	r.Status = func(msg string) {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgYellow, msg))
+	}
+
+	return r
+}
+
+func execDetach(params shell.Params, cmd string, args ...string) error {
+	var job, err = shell.NewJobID(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	instance, err := shell.Detach(context.Background(), params, job, cmd, args...)
+
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Started job %s on instance %s.\nFollow its output with:\n",
+		color.Format(color.Bold, job), color.Format(color.Bold, instance))
+
+	var remote string
+
+	if setupHost.Remote() != defaults.CloudRemote {
+		remote = " --remote " + setupHost.Remote()
+	}
+
+	fmt.Printf("lcp exec%s --project %s --service %s --instance %s --attach %s\n",
+		remote, params.ProjectID, params.ServiceID, instance, job)
+	return nil
+}
+
+func execAll(params shell.Params, cmd string, args ...string) error {
+	var ctx = context.Background()
+	var servicesClient = services.New(we.Context())
+
+	instances, err := servicesClient.Instances(ctx, params.ProjectID, params.ServiceID)
+
+	if err != nil {
+		return err
+	}
+
+	if len(instances) == 0 {
+		return fmt.Errorf("no instances found for service %s", params.ServiceID)
+	}
+
+	var ids = make([]string, len(instances))
+
+	for i, instance := range instances {
+		ids[i] = instance.ContainerID
+	}
+
+	var results = shell.RunAll(ctx, params, ids, concurrency, cmd, args...)
+	var failed int
+
+	for _, r := range results {
+		if r.ExitCode == 0 {
+			continue
+		}
+
+		failed++
+
+		if _, ok := r.Err.(*shell.ExitError); !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "Instance %s: %v\n", r.Instance, r.Err)
+		}
+	}
+
+	if failed == 0 {
+		return nil
+	}
+
+	return exiterror.New(
+		fmt.Sprintf("command failed on %d of %d instances", failed, len(results)),
+		shell.AggregateExitCode(results))
 }
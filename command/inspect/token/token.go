@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/command/internal/we"
@@ -39,22 +41,25 @@ func preRun(cmd *cobra.Command, args []string) error {
 
 func tokenRun(cmd *cobra.Command, args []string) error {
 	var wectx = we.Context()
-	var conf = wectx.Config()
-	var params = conf.GetParams()
-	var rl = params.Remotes
-	var remote = rl.Get(setupHost.Remote())
+	var token = wectx.Token()
 
-	if remote.Token == "" {
+	if token == "" {
 		return errors.New("user is not logged in")
 	}
 
+	t, err := usertoken.ParseUnsignedJSONWebToken(token)
+
 	if format == "" {
-		fmt.Println(remote.Token)
+		fmt.Println(token)
+
+		// on stderr, to keep the output usable on scripts
+		if lifetime := t.DescribeLifetime(time.Now()); err == nil && lifetime != "" {
+			_, _ = fmt.Fprintf(os.Stderr, "Token %s\n", lifetime)
+		}
+
 		return nil
 	}
 
-	t, err := usertoken.ParseUnsignedJSONWebToken(remote.Token)
-
 	if err != nil {
 		return err
 	}
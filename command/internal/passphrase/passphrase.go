@@ -0,0 +1,59 @@
+package passphrase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/envs"
+	"github.com/henvic/wedeploycli/fancy"
+	"github.com/henvic/wedeploycli/isterm"
+	"github.com/henvic/wedeploycli/prompt"
+)
+
+// Get passphrase for the secrets file from the environment or by prompting.
+// If confirm is true, the passphrase is asked twice when prompting.
+// Prompts are printed on stderr, so decrypted values can be piped safely.
+func Get(confirm bool) ([]byte, error) {
+	if p, ok := os.LookupEnv(envs.SecretsPassphrase); ok && p != "" {
+		return []byte(p), nil
+	}
+
+	if !isterm.Check() {
+		return nil, fmt.Errorf("can't prompt for passphrase: set %s instead", envs.SecretsPassphrase)
+	}
+
+	var p, err = ask("Passphrase for the secrets file:")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	if !confirm {
+		return []byte(p), nil
+	}
+
+	c, err := ask("Confirm passphrase:")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p != c {
+		return nil, errors.New("passphrases don't match")
+	}
+
+	return []byte(p), nil
+}
+
+func ask(question string) (string, error) {
+	_, _ = fmt.Fprintf(os.Stderr, "%s\n%s", fancy.Question(question), color.Format(color.FgHiBlack, "> "))
+	var p, err = prompt.Hidden()
+	_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgHiBlack, "●●●●●●●●●●"))
+	return p, err
+}
@@ -0,0 +1,132 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/ctxsignal"
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/logs"
+	"github.com/henvic/wedeploycli/logs/logfiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dir        string
+	rotateSize string
+	compress   bool
+	since      string
+)
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:    true,
+		Project: true,
+	},
+
+	PromptMissingProject: true,
+}
+
+// Cmd for exporting logs to local files
+var Cmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export logs to local files split per service and per day",
+	Long: `Export logs to local files split per service and per day
+
+Log files are written to <dir>/<project>/<service>/<yyyy-mm-dd>.<n>.log
+using UTC dates. A new segment starts when the day changes or the current
+segment reaches the rotation size. Logs are followed until interrupted.`,
+	Example: `  lcp log export --project acme-prd --dir ./logs
+  lcp log export --project acme-prd --service liferay --dir ./logs --rotate-size 100MB --compress`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+func init() {
+	setupHost.Init(Cmd)
+
+	Cmd.Flags().StringVar(&dir, "dir", "", "Directory to write the log files to")
+	Cmd.Flags().StringVar(&rotateSize, "rotate-size", "", "Start a new file after reaching a given size (i.e., 100MB)")
+	Cmd.Flags().BoolVar(&compress, "compress", false, "Compress finished files with gzip")
+	Cmd.Flags().StringVar(&since, "since", "", "Export since moment (i.e., 20min, 3h, UNIX timestamp)")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if dir == "" {
+		return errors.New("directory is required: use --dir")
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var rs, err = getRotateSize()
+
+	if err != nil {
+		return err
+	}
+
+	f := &logs.Filter{
+		Project: setupHost.Project(),
+	}
+
+	if setupHost.Service() != "" {
+		f.Services = strings.Split(setupHost.Service(), ",")
+	}
+
+	if f.Since, err = logs.ParseSince(since); err != nil {
+		return err
+	}
+
+	var w = &logfiles.Writer{
+		Dir:        dir,
+		RotateSize: rs,
+		Compress:   compress,
+	}
+
+	watcher := &logs.Watcher{
+		Filter:  f,
+		Handler: w.Write,
+	}
+
+	ctx, cancel := ctxsignal.WithTermination(context.Background())
+	defer cancel()
+
+	_, _ = fmt.Fprintf(os.Stderr, "Exporting logs to %s (press Ctrl+C to stop).\n", dir)
+
+	// Watch returns only after the last received log lines are handled.
+	if err := watcher.Watch(ctx, we.Context()); err != nil {
+		_ = w.Close()
+		return errwrap.Wrapf("can't write log files: {{err}}", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return errwrap.Wrapf("can't finish writing log files: {{err}}", err)
+	}
+
+	_, _ = fmt.Fprintln(os.Stderr, "\nLog export finished.")
+	return nil
+}
+
+func getRotateSize() (int64, error) {
+	if rotateSize == "" {
+		return 0, nil
+	}
+
+	var b, err = humanize.ParseBytes(rotateSize)
+
+	if err != nil {
+		return 0, errwrap.Wrapf("can't parse rotate-size argument: {{err}}", err)
+	}
+
+	return int64(b), nil
+}
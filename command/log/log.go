@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/henvic/ctxsignal"
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/command/internal/we"
+	cmdlogexport "github.com/henvic/wedeploycli/command/log/export"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/flagsfromhost"
 	"github.com/henvic/wedeploycli/logs"
+	"github.com/henvic/wedeploycli/projects"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +23,7 @@ var (
 	level string
 	since string
 	watch bool
+	hosts []string
 )
 
 var setupHost = cmdflagsfromhost.SetupHost{
@@ -33,6 +39,7 @@ var setupHost = cmdflagsfromhost.SetupHost{
 
 func init() {
 	setupHost.Init(LogCmd)
+	LogCmd.AddCommand(cmdlogexport.Cmd)
 }
 
 // LogCmd is used for getting logs about a given scope
@@ -46,10 +53,27 @@ var LogCmd = &cobra.Command{
   lcp log --service data
   lcp log --project chat --service data
   lcp log --url data-chat.lfr.cloud
-  lcp log --url data-chat.lfr.cloud --instance 10ab22`,
+  lcp log --url data-chat.lfr.cloud --instance 10ab22
+  lcp log --project acme-dev,acme-uat,acme-prd --service liferay
+  lcp log --project "acme-*"
+  lcp log --url liferay-acme-uat.lfr.cloud,search-acme-prd.lfr.cloud`,
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
+	var urlFlag = cmd.Flag("url")
+	hosts = splitList(urlFlag.Value.String())
+
+	if len(hosts) > 1 {
+		if cmd.Flag("instance").Changed {
+			return errors.New("incompatible use: --instance requires a single --url host")
+		}
+
+		// the other hosts are parsed by getHostsFilters, once the remote is known
+		if err := urlFlag.Value.Set(hosts[0]); err != nil {
+			return err
+		}
+	}
+
 	return setupHost.Process(context.Background(), we.Context())
 }
 
@@ -62,36 +86,48 @@ func logRun(cmd *cobra.Command, args []string) error {
 		return errors.New("invalid number of arguments")
 	}
 
-	var t, err = getSince()
+	var t, err = logs.ParseSince(since)
 
 	if err != nil {
 		return err
 	}
 
-	f := &logs.Filter{
-		Project:  project,
-		Instance: instance,
-		Level:    level,
-		Since:    t,
+	var filters []*logs.Filter
+
+	if len(hosts) > 1 {
+		filters, err = getHostsFilters(t)
+	} else {
+		filters, err = getProjectsFilters(project, service, instance, t)
 	}
 
-	if service != "" {
-		f.Services = strings.Split(service, ",")
+	if err != nil {
+		return err
 	}
 
 	if !watch {
 		logsClient := logs.New(we.Context())
-		return logsClient.List(context.Background(), f)
+
+		if len(filters) == 1 {
+			return logsClient.List(context.Background(), filters[0])
+		}
+
+		return logsClient.ListMerged(context.Background(), filters)
 	}
 
-	watcher := &logs.Watcher{
-		Filter: f,
+	watcher := &logs.Watcher{}
+
+	if len(filters) == 1 {
+		watcher.Filter = filters[0]
+	} else {
+		watcher.Filters = filters
 	}
 
 	ctx, cancel := ctxsignal.WithTermination(context.Background())
 	defer cancel()
 
-	watcher.Watch(ctx, we.Context())
+	if err := watcher.Watch(ctx, we.Context()); err != nil {
+		return err
+	}
 
 	if _, err := ctxsignal.Closed(ctx); err == nil {
 		fmt.Println()
@@ -100,19 +136,165 @@ func logRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func getSince() (string, error) {
-	if since == "" {
-		return "", nil
+func getProjectsFilters(project, service, instance, since string) ([]*logs.Filter, error) {
+	var projectIDs, err = expandProjects(project)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var filters = []*logs.Filter{}
+
+	for _, p := range projectIDs {
+		f := &logs.Filter{
+			Project:  p,
+			Instance: instance,
+			Level:    level,
+			Since:    since,
+		}
+
+		if service != "" {
+			f.Services = strings.Split(service, ",")
+		}
+
+		filters = append(filters, f)
 	}
 
-	t, err := logs.GetUnixTimestamp(since)
+	return filters, nil
+}
 
-	if err != nil {
-		return "", errwrap.Wrapf("can't parse since argument: {{err}}.", err)
+// getHostsFilters for a comma-separated list of hosts on --url, grouping services by project.
+// Glob patterns are only supported by --project.
+func getHostsFilters(since string) ([]*logs.Filter, error) {
+	var wectx = we.Context()
+	var params = wectx.Config().GetParams()
+	var cffh = flagsfromhost.New(params.Remotes)
+	var filters = []*logs.Filter{}
+	var byProject = map[string]*logs.Filter{}
+
+	for _, h := range hosts {
+		parsed, err := cffh.ParseWithDefaultCustomRemote(
+			flagsfromhost.ParseFlagsWithDefaultCustomRemote{
+				Host: h,
+			},
+			params.DefaultRemote)
+
+		if err != nil {
+			return nil, errwrap.Wrapf(`invalid host "`+h+`": {{err}}`, err)
+		}
+
+		var remote = parsed.Remote()
+
+		if remote == "" {
+			remote = defaults.CloudRemote
+		}
+
+		if remote != setupHost.Remote() {
+			return nil, fmt.Errorf(`can't use host "%s": all hosts must be on the same remote`, h)
+		}
+
+		if parsed.Project() == "" {
+			return nil, fmt.Errorf(`project is required for host "%s"`, h)
+		}
+
+		f, ok := byProject[parsed.Project()]
+
+		if !ok {
+			f = &logs.Filter{
+				Project: parsed.Project(),
+				Level:   level,
+				Since:   since,
+			}
+
+			if parsed.Service() != "" {
+				f.Services = []string{parsed.Service()}
+			}
+
+			byProject[parsed.Project()] = f
+			filters = append(filters, f)
+			continue
+		}
+
+		switch {
+		case parsed.Service() == "":
+			// all services of the project
+			f.Services = nil
+		case len(f.Services) != 0:
+			f.Services = appendUnique(f.Services, parsed.Service())
+		}
+	}
+
+	return filters, nil
+}
+
+// expandProjects from a comma-separated list of projects or glob patterns (i.e., acme-*).
+func expandProjects(project string) ([]string, error) {
+	var ids = []string{}
+	var available []projects.Project
+
+	for _, p := range splitList(project) {
+		if !strings.ContainsAny(p, "*?[") {
+			ids = appendUnique(ids, p)
+			continue
+		}
+
+		if available == nil {
+			var err error
+			available, err = projects.New(we.Context()).List(context.Background())
+
+			if err != nil {
+				return nil, errwrap.Wrapf("can't list projects: {{err}}", err)
+			}
+		}
+
+		var found bool
+
+		for _, a := range available {
+			matched, err := path.Match(p, a.ProjectID)
+
+			if err != nil {
+				return nil, errwrap.Wrapf("invalid project pattern: {{err}}", err)
+			}
+
+			if matched {
+				ids = appendUnique(ids, a.ProjectID)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf(`no project found matching "%s"`, p)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("project is required")
+	}
+
+	return ids, nil
+}
+
+// splitList of comma-separated values, ignoring empty and repeated values
+func splitList(s string) []string {
+	var list = []string{}
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = appendUnique(list, v)
+		}
+	}
+
+	return list
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, l := range list {
+		if l == s {
+			return list
+		}
 	}
 
-	// use nanoseconds instead of seconds (console takes ns as a param)
-	return fmt.Sprintf("%v000000000", t), err
+	return append(list, s)
 }
 
 func init() {
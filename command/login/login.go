@@ -5,23 +5,37 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/henvic/wedeploycli/apihelper"
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/command/internal/we"
 	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/credentials"
+	"github.com/henvic/wedeploycli/envs"
 	"github.com/henvic/wedeploycli/fancy"
 	"github.com/henvic/wedeploycli/login"
 	"github.com/henvic/wedeploycli/projects"
+	"github.com/henvic/wedeploycli/usertoken"
 	"github.com/spf13/cobra"
 )
 
-var noLaunchBrowser bool
+var (
+	noLaunchBrowser bool
+	device          bool
+)
 
 // LoginCmd sets the user credential
 var LoginCmd = &cobra.Command{
-	Use:     "login",
-	Short:   "Login into your account",
+	Use:   "login",
+	Short: "Login into your account",
+	Long: `Login into your account
+
+Tokens are saved on the OS keyring (Secret Service) when available, or on an
+encrypted file otherwise. Use ` + envs.CredentialsStore + ` or credentials_store on
+the configuration file to choose between keyring, file, and plaintext.
+
+` + credentials.MachineKeyNotice,
 	Args:    cobra.NoArgs,
 	PreRunE: preRun,
 	RunE:    loginRun,
@@ -34,6 +48,7 @@ var setupHost = cmdflagsfromhost.SetupHost{
 func init() {
 	setupHost.Init(LoginCmd)
 	LoginCmd.Flags().BoolVar(&noLaunchBrowser, "no-browser", false, "Perform the operation without opening your browser")
+	LoginCmd.Flags().BoolVar(&device, "device", false, "Log in by confirming a code on another device")
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
@@ -63,10 +78,34 @@ Logout first with "lcp logout"`,
 		wectx.InfrastructureDomain())
 }
 
+// isSessionExpiring checks if the session has expired or is about to expire, so it can be renewed
+func isSessionExpiring(wectx config.Context) bool {
+	var t, err = usertoken.ParseUnsignedJSONWebToken(wectx.Token())
+
+	if err != nil {
+		return false
+	}
+
+	var warning, _ = wectx.Config().SessionExpiryWarning()
+	var _, warn, errExpired = t.Check(time.Now(), warning)
+
+	if errExpired == nil && !warn {
+		return false
+	}
+
+	_, _ = fmt.Fprintln(os.Stderr, fancy.Info(
+		fmt.Sprintf("Session for %v on %v (%v) %s",
+			wectx.Username(),
+			wectx.Remote(),
+			wectx.InfrastructureDomain(),
+			t.DescribeLifetime(time.Now()))))
+	return true
+}
+
 func loginRun(cmd *cobra.Command, args []string) error {
 	var wectx = we.Context()
 
-	if wectx.Username() != "" {
+	if wectx.Username() != "" && !isSessionExpiring(wectx) {
 		if err := verifyAlreadyLoggedIn(wectx); err != nil {
 			return err
 		}
@@ -74,6 +113,7 @@ func loginRun(cmd *cobra.Command, args []string) error {
 
 	a := login.Authentication{
 		NoLaunchBrowser: noLaunchBrowser,
+		Device:          device,
 		TipCommands:     true,
 	}
 
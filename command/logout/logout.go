@@ -50,6 +50,7 @@ func logoutRun(cmd *cobra.Command, args []string) error {
 
 	remote.Username = ""
 	remote.Token = ""
+	remote.Credential = ""
 	rl.Set(wectx.Remote(), remote)
 	return conf.Save()
 }
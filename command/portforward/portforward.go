@@ -0,0 +1,113 @@
+package portforward
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/portforward"
+	"github.com/henvic/wedeploycli/shell"
+	"github.com/spf13/cobra"
+)
+
+var address string
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.FullHostPattern | cmdflagsfromhost.InstancePattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		Auth:     true,
+		Project:  true,
+		Service:  true,
+		Instance: true,
+	},
+
+	AutoSelectSingleInstance: true,
+
+	PromptMissingService:  true,
+	PromptMissingInstance: true,
+}
+
+// PortForwardCmd forwards local ports to service instances
+var PortForwardCmd = &cobra.Command{
+	Use:   "port-forward <local port>:<remote port>",
+	Short: "Forward a local port to an instance of your service",
+	Long: `Forward a local port to an instance of your service
+
+Each connection to the local port is tunneled to the port on the instance.
+The instance must have nc (netcat) installed.`,
+	Example: `  lcp port-forward -p acme-prd -s database 5432:5432
+  lcp port-forward -p acme-prd -s liferay --instance ab123 9999:9999`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+func init() {
+	setupHost.Init(PortForwardCmd)
+	PortForwardCmd.Flags().StringVar(&address, "address", "127.0.0.1", "Local address to listen on")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if _, err := portforward.ParsePorts(args[0]); err != nil {
+		return err
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var ports, _ = portforward.ParsePorts(args[0])
+
+	var wectx = we.Context()
+	var host = wectx.Infrastructure()
+
+	host = strings.Replace(host, "http://", "", 1)
+	host = strings.Replace(host, "https://", "", 1)
+
+	var params = shell.Params{
+		Host:  host,
+		Token: wectx.Token(),
+
+		ProjectID: setupHost.Project(),
+		ServiceID: setupHost.Service(),
+		Instance:  setupHost.Instance(),
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(ports.Local)))
+
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Forwarding %s to port %d on %s. Press Ctrl+C to stop.\n",
+		color.Format(color.Bold, l.Addr().String()),
+		ports.Remote,
+		color.Format(color.Bold, setupHost.Host()))
+
+	var f = &portforward.Forwarder{
+		Run:    portforward.Exec(params, ports.Remote),
+		Status: printStatus,
+	}
+
+	return f.Serve(context.Background(), l)
+}
+
+func printStatus(conn net.Conn, err error, closed bool) {
+	var remote = conn.RemoteAddr().String()
+
+	switch {
+	case err != nil:
+		_, _ = fmt.Fprintf(os.Stderr, "%s %s: %v\n", color.Format(color.FgRed, "Connection failed"), remote, err)
+	case closed:
+		_, _ = fmt.Fprintf(os.Stderr, "%s %s\n", color.Format(color.FgHiBlack, "Connection closed"), remote)
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "%s %s\n", color.Format(color.FgHiBlack, "Handling connection from"), remote)
+	}
+}
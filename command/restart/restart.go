@@ -2,12 +2,16 @@ package restart
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/color"
 	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/rolling"
 	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/verbose"
 	"github.com/spf13/cobra"
 )
 
@@ -18,11 +22,25 @@ var RestartCmd = &cobra.Command{
 	Args:    cobra.NoArgs,
 	PreRunE: preRun,
 	RunE:    restartRun,
+	Long: `Restart services
+
+Use --all to restart all services of a project, ordered by the dependencies
+defined on the LCP.json files of the current directory.
+With --rolling, services are restarted one at a time, waiting for each one to be
+healthy before moving on, and aborting on failure.`,
 	Example: `  lcp restart --project chat --service data
 ^  lcp restart --project chat --service data --remote lfr-cloud
-^  lcp restart --url data-chat.lfr.cloud`,
+^  lcp restart --url data-chat.lfr.cloud
+^  lcp restart --project chat --all --rolling --timeout 10m`,
 }
 
+var (
+	all         bool
+	rollingFlag bool
+	waitHealthy bool
+	timeout     time.Duration
+)
+
 var setupHost = cmdflagsfromhost.SetupHost{
 	Pattern: cmdflagsfromhost.FullHostPattern,
 
@@ -42,6 +60,11 @@ func init() {
 	// the --quiet parameter was removed
 	_ = RestartCmd.Flags().BoolP("quiet", "q", false, "")
 	_ = RestartCmd.Flags().MarkHidden("quiet")
+
+	RestartCmd.Flags().BoolVar(&all, "all", false, "Restart all services of the project")
+	RestartCmd.Flags().BoolVar(&rollingFlag, "rolling", false, "Restart one service at a time, waiting for each one to be healthy")
+	RestartCmd.Flags().BoolVar(&waitHealthy, "wait-healthy", false, "Wait for services to be healthy")
+	RestartCmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "Timeout for each service to be healthy")
 }
 
 type restart struct {
@@ -79,10 +102,27 @@ func (r *restart) checkServiceExists() (err error) {
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
-	return setupHost.Process(context.Background(), we.Context())
+	if all {
+		setupHost.Requires.Service = false
+		setupHost.PromptMissingService = false
+	}
+
+	if err := setupHost.Process(context.Background(), we.Context()); err != nil {
+		return err
+	}
+
+	if all && setupHost.Service() != "" {
+		return errors.New("incompatible use: --service cannot be used with --all")
+	}
+
+	return nil
 }
 
 func restartRun(cmd *cobra.Command, args []string) error {
+	if all || rollingFlag || waitHealthy {
+		return restartMany()
+	}
+
 	var r = &restart{
 		project: setupHost.Project(),
 		service: setupHost.Service(),
@@ -95,3 +135,109 @@ func restartRun(cmd *cobra.Command, args []string) error {
 
 	return r.do()
 }
+
+func restartMany() error {
+	var ctx = context.Background()
+	var wectx = we.Context()
+	var servicesClient = services.New(wectx)
+	var ids = []string{setupHost.Service()}
+
+	if all {
+		var err error
+
+		if ids, err = getOrderedServices(ctx, servicesClient); err != nil {
+			return err
+		}
+	}
+
+	var r = &rolling.Restarter{
+		Client:  servicesClient,
+		Timeout: timeout,
+		Wait:    rollingFlag,
+		Status:  (&statusPrinter{}).print,
+	}
+
+	if rollingFlag {
+		return r.Restart(ctx, setupHost.Project(), ids)
+	}
+
+	for _, id := range ids {
+		if err := r.RestartOne(ctx, setupHost.Project(), id); err != nil {
+			return err
+		}
+	}
+
+	if !waitHealthy {
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := r.WaitHealthy(ctx, setupHost.Project(), id); err != nil {
+			return fmt.Errorf("%s: %v", id, err)
+		}
+
+		r.Status(id, "healthy")
+	}
+
+	return nil
+}
+
+func getOrderedServices(ctx context.Context, servicesClient *services.Client) ([]string, error) {
+	var list, err = servicesClient.List(ctx, setupHost.Project())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, errors.New("no services found on project " + setupHost.Project())
+	}
+
+	var ids = []string{}
+
+	for _, s := range list {
+		ids = append(ids, s.ServiceID)
+	}
+
+	var pkgs []services.Package
+	local, err := services.GetListFromDirectory(".")
+
+	if err != nil {
+		verbose.Debug("Can't read local services for dependencies order: " + err.Error())
+	}
+
+	for _, l := range local {
+		if l.ProjectID == "" || l.ProjectID == setupHost.Project() {
+			pkgs = append(pkgs, l.Package())
+		}
+	}
+
+	return rolling.Order(ids, pkgs)
+}
+
+type statusPrinter struct {
+	last map[string]string
+}
+
+func (s *statusPrinter) print(serviceID, status string) {
+	if s.last == nil {
+		s.last = map[string]string{}
+	}
+
+	if s.last[serviceID] == status {
+		return
+	}
+
+	s.last[serviceID] = status
+
+	var c = color.FgHiBlack
+
+	switch status {
+	case "healthy", "restarted":
+		c = color.FgGreen
+	case "failed":
+		c = color.FgRed
+	}
+
+	fmt.Printf("%s %s\n", color.Format(color.Bold, serviceID), color.Format(c, status))
+}
@@ -3,12 +3,15 @@ package root
 import (
 	"github.com/henvic/wedeploycli/command/about"
 	"github.com/henvic/wedeploycli/command/activities"
+	"github.com/henvic/wedeploycli/command/apply"
 	"github.com/henvic/wedeploycli/command/autocomplete"
 	"github.com/henvic/wedeploycli/command/console"
+	"github.com/henvic/wedeploycli/command/cp"
 	"github.com/henvic/wedeploycli/command/curl"
 	"github.com/henvic/wedeploycli/command/delete"
 	"github.com/henvic/wedeploycli/command/deploy"
 	"github.com/henvic/wedeploycli/command/diagnostics"
+	"github.com/henvic/wedeploycli/command/diff"
 	"github.com/henvic/wedeploycli/command/docs"
 	"github.com/henvic/wedeploycli/command/domain"
 	"github.com/henvic/wedeploycli/command/env-var"
@@ -21,9 +24,12 @@ import (
 	"github.com/henvic/wedeploycli/command/metrics"
 	"github.com/henvic/wedeploycli/command/new"
 	"github.com/henvic/wedeploycli/command/open"
+	"github.com/henvic/wedeploycli/command/portforward"
 	"github.com/henvic/wedeploycli/command/remote"
 	"github.com/henvic/wedeploycli/command/restart"
 	"github.com/henvic/wedeploycli/command/scale"
+	"github.com/henvic/wedeploycli/command/secrets"
+	"github.com/henvic/wedeploycli/command/service"
 	"github.com/henvic/wedeploycli/command/shell"
 	"github.com/henvic/wedeploycli/command/uninstall"
 	"github.com/henvic/wedeploycli/command/update"
@@ -35,16 +41,22 @@ import (
 var commands = []*cobra.Command{
 	activities.ActivitiesCmd,
 	deploy.DeployCmd,
+	diff.DiffCmd,
+	apply.ApplyCmd,
 	list.ListCmd,
 	new.NewCmd,
 	log.LogCmd,
 	domain.DomainCmd,
 	env.EnvCmd,
+	secrets.SecretsCmd,
+	service.ServiceCmd,
 	scale.ScaleCmd,
 	restart.RestartCmd,
 	delete.DeleteCmd,
 	exec.ExecCmd,
 	shell.ShellCmd,
+	cp.CpCmd,
+	portforward.PortForwardCmd,
 	login.LoginCmd,
 	logout.LogoutCmd,
 	open.OpenCmd,
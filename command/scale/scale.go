@@ -2,8 +2,12 @@ package scale
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/color"
@@ -11,6 +15,7 @@ import (
 	"github.com/henvic/wedeploycli/fancy"
 	"github.com/henvic/wedeploycli/isterm"
 	"github.com/henvic/wedeploycli/list"
+	"github.com/henvic/wedeploycli/scaling"
 	"github.com/henvic/wedeploycli/services"
 	"github.com/spf13/cobra"
 )
@@ -20,11 +25,26 @@ var ScaleCmd = &cobra.Command{
 	Use:   "scale",
 	Short: "Configure number of instances for services",
 	RunE:  scaleRun,
+	Long: `Configure number of instances for services
+
+Multiple services can be changed at once with service=count pairs, or all services
+of a project with --all. Changes are applied in parallel and a summary is shown.
+Use --cpu and --memory to change the resources of the services.`,
 	Example: `  lcp scale --project chat --service data 3
   lcp scale --project chat --service data --remote lfr-cloud 5
-  lcp scale --url data-chat.lfr.cloud 1`,
+  lcp scale --url data-chat.lfr.cloud 1
+  lcp scale --project chat data=3 web=2
+  lcp scale --project chat --all 1
+  lcp scale --project chat --service data --cpu 2 --memory 4096`,
 }
 
+var (
+	all         bool
+	cpu         string
+	memory      string
+	concurrency int
+)
+
 var setupHost = cmdflagsfromhost.SetupHost{
 	Pattern: cmdflagsfromhost.FullHostPattern,
 
@@ -42,6 +62,10 @@ var setupHost = cmdflagsfromhost.SetupHost{
 
 func init() {
 	setupHost.Init(ScaleCmd)
+	ScaleCmd.Flags().BoolVar(&all, "all", false, "Change all services of the project")
+	ScaleCmd.Flags().StringVar(&cpu, "cpu", "", "Number of CPUs for each instance")
+	ScaleCmd.Flags().StringVar(&memory, "memory", "", "Memory (in MB) for each instance")
+	ScaleCmd.Flags().IntVar(&concurrency, "parallel", scaling.DefaultConcurrency, "Number of services changed at the same time")
 }
 
 func getInstancesNumber(cmd *cobra.Command, args []string) (string, error) {
@@ -112,11 +136,54 @@ func isValidNumberOfInstances(instances string) bool {
 	return err == nil && n > 0
 }
 
+func hasPairs(args []string) bool {
+	for _, a := range args {
+		if strings.Contains(a, "=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkResources() error {
+	for name, v := range map[string]string{"cpu": cpu, "memory": memory} {
+		if v == "" {
+			continue
+		}
+
+		if f, err := strconv.ParseFloat(v, 64); err != nil || f <= 0 {
+			return fmt.Errorf(`"%v" isn't a valid value for --%s`, v, name)
+		}
+	}
+
+	return nil
+}
+
 func scaleRun(cmd *cobra.Command, args []string) error {
+	if err := checkResources(); err != nil {
+		return err
+	}
+
+	var bulk = all || hasPairs(args)
+
+	if bulk {
+		setupHost.Requires.Service = false
+		setupHost.PromptMissingService = false
+	}
+
 	if err := setupHost.Process(context.Background(), we.Context()); err != nil {
 		return err
 	}
 
+	if bulk {
+		return bulkRun(args)
+	}
+
+	if cpu != "" || memory != "" {
+		return resourcesRun(args)
+	}
+
 	sscale, err := getInstancesNumber(cmd, args)
 
 	if err != nil {
@@ -147,3 +214,121 @@ func scaleRun(cmd *cobra.Command, args []string) error {
 
 	return s.do()
 }
+
+func resourcesRun(args []string) error {
+	if err := cobra.MaximumNArgs(1)(nil, args); err != nil {
+		return err
+	}
+
+	var c = scaling.Change{
+		ServiceID: setupHost.Service(),
+		CPU:       json.Number(cpu),
+		Memory:    json.Number(memory),
+	}
+
+	if len(args) != 0 {
+		var err error
+
+		if c.Scale, err = scaling.ParseScale(args[0]); err != nil {
+			return err
+		}
+	}
+
+	return apply([]scaling.Change{c})
+}
+
+func bulkRun(args []string) error {
+	if setupHost.Service() != "" {
+		return errors.New("incompatible use: --service cannot be used with --all or service=count pairs")
+	}
+
+	if !all {
+		changes, err := scaling.ParsePairs(args)
+
+		if err != nil {
+			return err
+		}
+
+		for i := range changes {
+			changes[i].CPU = json.Number(cpu)
+			changes[i].Memory = json.Number(memory)
+		}
+
+		return apply(changes)
+	}
+
+	changes, err := getAllChanges(args)
+
+	if err != nil {
+		return err
+	}
+
+	return apply(changes)
+}
+
+func getAllChanges(args []string) ([]scaling.Change, error) {
+	if hasPairs(args) {
+		return nil, errors.New("incompatible use: --all cannot be used with service=count pairs")
+	}
+
+	if err := cobra.MaximumNArgs(1)(nil, args); err != nil {
+		return nil, err
+	}
+
+	var n int
+
+	if len(args) != 0 {
+		var err error
+
+		if n, err = scaling.ParseScale(args[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	if n == 0 && cpu == "" && memory == "" {
+		return nil, scaling.ErrNoChanges
+	}
+
+	var servicesClient = services.New(we.Context())
+	var list, err = servicesClient.List(context.Background(), setupHost.Project())
+
+	if err != nil {
+		return nil, err
+	}
+
+	var changes = []scaling.Change{}
+
+	for _, s := range list {
+		changes = append(changes, scaling.Change{
+			ServiceID: s.ServiceID,
+			Scale:     n,
+			CPU:       json.Number(cpu),
+			Memory:    json.Number(memory),
+		})
+	}
+
+	return changes, nil
+}
+
+func apply(changes []scaling.Change) error {
+	var s = scaling.Scaler{
+		Client:      services.New(we.Context()),
+		Concurrency: concurrency,
+	}
+
+	var results, err = s.Apply(context.Background(), setupHost.Project(), changes)
+
+	if err != nil {
+		return err
+	}
+
+	if err := scaling.Summary(os.Stdout, results); err != nil {
+		return err
+	}
+
+	if failed := scaling.Failed(results); failed != 0 {
+		return fmt.Errorf("%d of %d services failed to change", failed, len(results))
+	}
+
+	return nil
+}
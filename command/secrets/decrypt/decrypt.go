@@ -0,0 +1,82 @@
+package decrypt
+
+import (
+	"io"
+	"os"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/command/internal/passphrase"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/secrets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	file   string
+	format string
+	output string
+)
+
+// Cmd for decrypting secrets
+var Cmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt secrets to a plain text format",
+	Example: `  lcp secrets decrypt
+  lcp secrets decrypt --format json
+  lcp secrets decrypt --output .env`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&file, "file", secrets.Filename, "Secrets file")
+	Cmd.Flags().StringVarP(&format, "format", "f", string(envfile.Dotenv), "Output format (dotenv, json, yaml)")
+	Cmd.Flags().StringVarP(&output, "output", "o", "", "Write to file instead of stdout")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	_, err := envfile.ParseFormat(format)
+	return err
+}
+
+func run(cmd *cobra.Command, args []string) (err error) {
+	sf, err := secrets.Read(file)
+
+	if err != nil {
+		return err
+	}
+
+	p, err := passphrase.Get(false)
+
+	if err != nil {
+		return err
+	}
+
+	envs, err := sf.Decrypt(p)
+
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+
+	if output != "" {
+		of, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+
+		if err != nil {
+			return errwrap.Wrapf("can't create output file: {{err}}", err)
+		}
+
+		defer func() {
+			if ec := of.Close(); ec != nil && err == nil {
+				err = ec
+			}
+		}()
+
+		w = of
+	}
+
+	var f, _ = envfile.ParseFormat(format)
+	return envfile.Encode(w, envs, f)
+}
@@ -0,0 +1,173 @@
+package edit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/passphrase"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/secrets"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/verbose"
+	"github.com/spf13/cobra"
+)
+
+var file string
+
+// Cmd for editing secrets
+var Cmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit secrets with your text editor",
+	Long: `Edit secrets with your text editor
+
+Secrets are decrypted to a temporary dotenv file opened with $VISUAL or $EDITOR.
+The file is encrypted again once the editor is closed, and the temporary file is removed.
+If the edited file can't be parsed, it is kept so the changes aren't lost.
+If the secrets file doesn't exist, it is created.`,
+	Example: `  lcp secrets edit
+  EDITOR="code --wait" lcp secrets edit --file prd/LCP.secrets.json`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&file, "file", secrets.Filename, "Secrets file")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var previous *secrets.File
+	var envs = []services.EnvironmentVariable{}
+	var err error
+
+	if secrets.Exists(file) {
+		if previous, err = secrets.Read(file); err != nil {
+			return err
+		}
+	}
+
+	p, err := passphrase.Get(previous == nil)
+
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		if envs, err = previous.Decrypt(p); err != nil {
+			return err
+		}
+	}
+
+	edited, err := edit(envs)
+
+	if err != nil {
+		return err
+	}
+
+	if changes := envfile.Diff(envs, edited); changes.Empty() && previous != nil {
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	f, err := secrets.Encrypt(edited, p, previous)
+
+	if err != nil {
+		return err
+	}
+
+	if err := f.Write(file); err != nil {
+		return errwrap.Wrapf("can't write secrets file: {{err}}", err)
+	}
+
+	fmt.Printf("%d secrets saved on %s.\n", len(f.Env), color.Format(color.Bold, file))
+	return nil
+}
+
+func edit(envs []services.EnvironmentVariable) ([]services.EnvironmentVariable, error) {
+	var tmp, err = ioutil.TempFile("", "lcp-secrets-*.env")
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't create temporary file: {{err}}", err)
+	}
+
+	// the edited file is kept if it can't be parsed, so the changes aren't lost
+	var keep bool
+
+	defer func() {
+		if keep {
+			return
+		}
+
+		if er := os.Remove(tmp.Name()); er != nil {
+			verbose.Debug("can't remove temporary file: " + er.Error())
+		}
+	}()
+
+	err = envfile.Encode(tmp, envs, envfile.Dotenv)
+
+	if ec := tmp.Close(); err == nil {
+		err = ec
+	}
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't write temporary file: {{err}}", err)
+	}
+
+	if err := runEditor(tmp.Name()); err != nil {
+		return nil, err
+	}
+
+	r, err := os.Open(tmp.Name())
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	edited, err := envfile.Decode(r, envfile.Dotenv)
+
+	if err != nil {
+		keep = true
+		return nil, errwrap.Wrapf("can't parse edited secrets: {{err}}\n"+
+			"Your changes were kept on "+tmp.Name()+": fix it and use \"lcp secrets encrypt "+tmp.Name()+
+			" --file "+file+"\", then remove it", err)
+	}
+
+	return edited, nil
+}
+
+func getEditor() []string {
+	for _, e := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.Fields(os.Getenv(e)); len(v) != 0 {
+			return v
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
+
+func runEditor(path string) error {
+	var editor = getEditor()
+	var c = exec.Command(editor[0], append(editor[1:], path)...) // #nosec
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	if err := c.Run(); err != nil {
+		return errwrap.Wrapf("editor exited with error: {{err}}", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package encrypt
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/passphrase"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/secrets"
+	"github.com/spf13/cobra"
+)
+
+var (
+	file   string
+	format string
+)
+
+// Cmd for encrypting secrets
+var Cmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt environment variables from a plain text file",
+	Long: `Encrypt environment variables from a plain text file into LCP.secrets.json
+
+The file format is guessed from the file extension (.json, .yaml, .yml, or dotenv otherwise).
+If the secrets file already exists, it is replaced, keeping the encrypted values of unchanged variables.
+Don't commit the plain text file.`,
+	Example: `  lcp secrets encrypt .env
+  lcp secrets encrypt secrets.yaml --file prd/LCP.secrets.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&file, "file", secrets.Filename, "Secrets file")
+	Cmd.Flags().StringVarP(&format, "format", "f", "", "Plain text file format (dotenv, json, yaml)")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var envs, err = envfile.ReadFile(args[0], format)
+
+	if err != nil {
+		return err
+	}
+
+	var previous *secrets.File
+
+	if secrets.Exists(file) {
+		if previous, err = secrets.Read(file); err != nil {
+			return err
+		}
+	}
+
+	p, err := passphrase.Get(previous == nil)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := secrets.Encrypt(envs, p, previous)
+
+	if err != nil {
+		return err
+	}
+
+	if err := f.Write(file); err != nil {
+		return errwrap.Wrapf("can't write secrets file: {{err}}", err)
+	}
+
+	fmt.Printf("%d secrets encrypted on %s.\n", len(f.Env), color.Format(color.Bold, file))
+	return nil
+}
@@ -0,0 +1,31 @@
+package secrets
+
+import (
+	cmdsecretsdecrypt "github.com/henvic/wedeploycli/command/secrets/decrypt"
+	cmdsecretsedit "github.com/henvic/wedeploycli/command/secrets/edit"
+	cmdsecretsencrypt "github.com/henvic/wedeploycli/command/secrets/encrypt"
+	"github.com/spf13/cobra"
+)
+
+// SecretsCmd manages the encrypted secrets file
+var SecretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage encrypted secrets versioned alongside LCP.json",
+	Long: `Manage encrypted secrets versioned alongside LCP.json
+
+Secrets are stored on LCP.secrets.json with their values encrypted with a key derived from a passphrase.
+Variable names are kept in clear text, so changes can be reviewed without revealing values.
+Use "lcp env-var sync" to apply them as environment variables of a service.
+
+Set WEDEPLOY_SECRETS_PASSPHRASE to use a passphrase without prompting.`,
+	Example: `  lcp secrets encrypt .env
+  lcp secrets edit
+  lcp secrets decrypt --format json`,
+	Args: cobra.NoArgs,
+}
+
+func init() {
+	SecretsCmd.AddCommand(cmdsecretsencrypt.Cmd)
+	SecretsCmd.AddCommand(cmdsecretsdecrypt.Cmd)
+	SecretsCmd.AddCommand(cmdsecretsedit.Cmd)
+}
@@ -0,0 +1,104 @@
+package clone
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/cloning"
+	"github.com/henvic/wedeploycli/cmdflagsfromhost"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/spf13/cobra"
+)
+
+var (
+	from       string
+	to         string
+	excludeEnv []string
+	domains    bool
+)
+
+// Cmd for cloning a service
+var Cmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone a service configuration to another project or environment",
+	Long: `Clone a service configuration to another project or environment
+
+Creates a new service with the image, cpu, memory, scale, and environment variables
+of an existing service. Custom domains are only copied with --domains.`,
+	Example: `  lcp service clone --from acme-uat/search --to acme-dev/search
+  lcp service clone --from acme-uat/liferay --to acme-dev/liferay --exclude-env "LCP_UAT_*" --exclude-env "*_SECRET"`,
+	Args:    cobra.NoArgs,
+	PreRunE: preRun,
+	RunE:    run,
+}
+
+var setupHost = cmdflagsfromhost.SetupHost{
+	Pattern: cmdflagsfromhost.RemotePattern,
+
+	Requires: cmdflagsfromhost.Requires{
+		NoHost: true,
+		Auth:   true,
+	},
+}
+
+func init() {
+	setupHost.Init(Cmd)
+	Cmd.Flags().StringVar(&from, "from", "", "Service to clone (<project/service>)")
+	Cmd.Flags().StringVar(&to, "to", "", "New service (<project/service>)")
+	Cmd.Flags().StringArrayVar(&excludeEnv, "exclude-env", nil, "Pattern of environment variables names not to copy")
+	Cmd.Flags().BoolVar(&domains, "domains", false, "Copy custom domains")
+}
+
+func preRun(cmd *cobra.Command, args []string) error {
+	if _, err := cmdflagsfromhost.ParseServiceTarget(from); err != nil {
+		return errwrap.Wrapf("invalid --from value: {{err}}", err)
+	}
+
+	if _, err := cmdflagsfromhost.ParseServiceTarget(to); err != nil {
+		return errwrap.Wrapf("invalid --to value: {{err}}", err)
+	}
+
+	return setupHost.Process(context.Background(), we.Context())
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var src, _ = cmdflagsfromhost.ParseServiceTarget(from)
+	var dst, _ = cmdflagsfromhost.ParseServiceTarget(to)
+
+	if src == dst {
+		return fmt.Errorf("can't clone %s to itself", src)
+	}
+
+	var wectx = we.Context()
+
+	var c = cloning.Cloner{
+		Client: services.New(wectx),
+	}
+
+	var s, err = c.Clone(context.Background(), src.Project, src.Service, dst.Project, dst.Service, cloning.Options{
+		ExcludeEnv: excludeEnv,
+		Domains:    domains,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(color.Format(color.FgHiBlack, "Service \"")+
+		"%s"+
+		color.Format(color.FgHiBlack, "\" cloned to \"")+
+		"%s-%s.%s"+
+		color.Format(color.FgHiBlack, "\" on ")+
+		wectx.InfrastructureDomain()+
+		color.Format(color.FgHiBlack, ".")+
+		"\n",
+		src,
+		s.ServiceID,
+		dst.Project,
+		wectx.ServiceDomain())
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	cmdserviceclone "github.com/henvic/wedeploycli/command/service/clone"
+	"github.com/spf13/cobra"
+)
+
+// ServiceCmd manages services
+var ServiceCmd = &cobra.Command{
+	Use:     "service",
+	Short:   "Manage services",
+	Example: `  lcp service clone --from acme-uat/search --to acme-dev/search`,
+	Args:    cobra.NoArgs,
+}
+
+func init() {
+	ServiceCmd.AddCommand(cmdserviceclone.Cmd)
+}
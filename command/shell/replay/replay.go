@@ -0,0 +1,55 @@
+package replay
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/henvic/wedeploycli/asciicast"
+	"github.com/spf13/cobra"
+)
+
+var (
+	speed     float64
+	idleLimit time.Duration
+)
+
+// Cmd for replaying recorded shell sessions
+var Cmd = &cobra.Command{
+	Use:   "replay <recording>",
+	Short: "Replay a recorded shell session",
+	Example: `  lcp shell replay session.cast
+  lcp shell replay session.cast --speed 2 --idle-limit 1s`,
+	Args: cobra.ExactArgs(1),
+	RunE: run,
+}
+
+func init() {
+	Cmd.Flags().Float64Var(&speed, "speed", 1, "Playback speed multiplier")
+	Cmd.Flags().DurationVar(&idleLimit, "idle-limit", 0, "Limit the time waiting between outputs")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	r, err := asciicast.NewReader(f)
+
+	if err != nil {
+		return err
+	}
+
+	var p = &asciicast.Player{
+		Speed:   speed,
+		MaxIdle: idleLimit,
+	}
+
+	return p.Play(context.Background(), os.Stdout, r)
+}
@@ -3,15 +3,23 @@ package shell
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"strings"
 
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/asciicast"
 	"github.com/henvic/wedeploycli/cmdflagsfromhost"
 	"github.com/henvic/wedeploycli/command/internal/we"
+	cmdshellreplay "github.com/henvic/wedeploycli/command/shell/replay"
 	"github.com/henvic/wedeploycli/isterm"
 	"github.com/henvic/wedeploycli/shell"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
+var record string
+
 var setupHost = cmdflagsfromhost.SetupHost{
 	Pattern: cmdflagsfromhost.FullHostPattern | cmdflagsfromhost.InstancePattern,
 
@@ -32,6 +40,9 @@ var ShellCmd = &cobra.Command{
 	Use:     "shell",
 	Aliases: []string{"ssh"},
 	Short:   "Opens a shell on a container of your service\n\t\t",
+	Example: `  lcp shell -p acme-prd -s liferay
+  lcp shell -p acme-prd -s liferay --record session.cast
+  lcp shell replay session.cast`,
 	PreRunE: shellPreRun,
 	RunE:    shellRun,
 	Args:    cobra.NoArgs,
@@ -39,6 +50,8 @@ var ShellCmd = &cobra.Command{
 
 func init() {
 	setupHost.Init(ShellCmd)
+	ShellCmd.Flags().StringVar(&record, "record", "", "Record the session output to a file (asciinema v2 format)")
+	ShellCmd.AddCommand(cmdshellreplay.Cmd)
 }
 
 func shellPreRun(cmd *cobra.Command, args []string) error {
@@ -68,5 +81,50 @@ func shellRun(cmd *cobra.Command, args []string) error {
 		TTY:         true,
 	}
 
-	return shell.Run(context.Background(), params, "")
+	if record == "" {
+		return shell.Run(context.Background(), params, "")
+	}
+
+	return recordRun(params)
+}
+
+func recordRun(params shell.Params) (err error) {
+	f, err := os.OpenFile(record, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return errwrap.Wrapf("can't create recording file: {{err}}", err)
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = errwrap.Wrapf("can't save recording: {{err}}", cerr)
+		}
+	}()
+
+	var width, height, _ = terminal.GetSize(int(os.Stdin.Fd()))
+	var wectx = we.Context()
+
+	r, err := asciicast.NewRecorder(f, asciicast.Header{
+		Width:  width,
+		Height: height,
+		Title:  fmt.Sprintf("%s on %s", wectx.Username(), setupHost.Host()),
+		Env: map[string]string{
+			"TERM": os.Getenv("TERM"),
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	params.Stdout = r.Output(os.Stdout)
+	params.Stderr = r.Output(os.Stderr)
+
+	err = shell.Run(context.Background(), params, "")
+
+	if rerr := r.Err(); rerr != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Recording to %s failed: %v\n", record, rerr)
+	}
+
+	return err
 }
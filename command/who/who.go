@@ -3,8 +3,10 @@ package who
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/henvic/wedeploycli/command/internal/we"
+	"github.com/henvic/wedeploycli/usertoken"
 	"github.com/spf13/cobra"
 )
 
@@ -23,12 +25,25 @@ func whoRun(cmd *cobra.Command, args []string) error {
 			wectx.Username(),
 			wectx.Remote(),
 			wectx.InfrastructureDomain())
+		printLifetime(wectx.Token())
 		return nil
 	}
 
 	return errors.New("user is not available")
 }
 
+func printLifetime(token string) {
+	var t, err = usertoken.ParseUnsignedJSONWebToken(token)
+
+	if err != nil {
+		return
+	}
+
+	if lifetime := t.DescribeLifetime(time.Now()); lifetime != "" {
+		fmt.Printf("Session %s\n", lifetime)
+	}
+}
+
 func init() {
 	WhoCmd.Hidden = true
 }
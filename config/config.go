@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/errwrap"
 	version "github.com/hashicorp/go-version"
 	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/credentials"
 	"github.com/henvic/wedeploycli/defaults"
 	"github.com/henvic/wedeploycli/remotes"
 	"github.com/henvic/wedeploycli/verbose"
@@ -23,8 +24,15 @@ type Config struct {
 
 	Params Params
 
+	// Credentials store for tokens (opened from the credentials_store parameter when nil)
+	Credentials credentials.Store
+
 	m    sync.Mutex
 	file *ini.File
+
+	stores   map[string]credentials.Store
+	resolved map[string]string
+	known    map[string]bool
 }
 
 // GetParams gets the configuration parameters in a concurrent safe way.
@@ -43,18 +51,22 @@ func (c *Config) SetParams(p Params) {
 
 // Params for the configuration
 type Params struct {
-	DefaultRemote   string        `ini:"default_remote"`
-	NoAutocomplete  bool          `ini:"disable_autocomplete_autoinstall"`
-	NoColor         bool          `ini:"disable_colors"`
-	NotifyUpdates   bool          `ini:"notify_updates"`
-	ReleaseChannel  string        `ini:"release_channel"`
-	LastUpdateCheck string        `ini:"last_update_check"`
-	PastVersion     string        `ini:"past_version"`
-	NextVersion     string        `ini:"next_version"`
-	EnableAnalytics bool          `ini:"enable_analytics"`
-	AnalyticsID     string        `ini:"analytics_id"`
-	EnableCURL      bool          `ini:"enable_curl"`
-	Remotes         *remotes.List `ini:"-"`
+	DefaultRemote    string        `ini:"default_remote"`
+	NoAutocomplete   bool          `ini:"disable_autocomplete_autoinstall"`
+	NoColor          bool          `ini:"disable_colors"`
+	NotifyUpdates    bool          `ini:"notify_updates"`
+	ReleaseChannel   string        `ini:"release_channel"`
+	LastUpdateCheck  string        `ini:"last_update_check"`
+	PastVersion      string        `ini:"past_version"`
+	NextVersion      string        `ini:"next_version"`
+	EnableAnalytics  bool          `ini:"enable_analytics"`
+	AnalyticsID      string        `ini:"analytics_id"`
+	EnableCURL       bool          `ini:"enable_curl"`
+	MaskEnvPatterns  string        `ini:"mask_env_patterns"`
+	MaskAllEnvs      bool          `ini:"mask_all_env_values"`
+	CredentialsStore string        `ini:"credentials_store"`
+	SessionExpiry    string        `ini:"session_expiry_warning"`
+	Remotes          *remotes.List `ini:"-"`
 }
 
 var parseRemoteSectionNameRegex = regexp.MustCompile(`remote \"(.*)\"`)
@@ -133,6 +145,12 @@ func (c *Config) Save() error {
 		return errwrap.Wrapf("can't load configuration: {{err}}", err)
 	}
 
+	if err = c.storeCredentials(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgYellow,
+			"Warning: %v\nTokens that can't be saved on the credentials store are kept as plaintext on %s.",
+			err, c.Path))
+	}
+
 	c.updateRemotes()
 	c.simplify()
 
@@ -274,6 +292,7 @@ func (c *Config) readRemotes() {
 		service := remote.Key("service")
 		username := remote.Key("username")
 		token := remote.Key("token")
+		credential := remote.Key("credential")
 		comment := remote.Comment
 
 		rl.Set(k, remotes.Entry{
@@ -286,7 +305,10 @@ func (c *Config) readRemotes() {
 			UsernameComment:       strings.TrimSpace(username.Comment),
 			Token:                 strings.TrimSpace(token.String()),
 			TokenComment:          strings.TrimSpace(token.Comment),
+			Credential:            strings.TrimSpace(credential.String()),
 		})
+
+		c.addKnownCredential(rl.Get(k).Credential)
 	}
 
 	var params = c.GetParams()
@@ -296,7 +318,8 @@ func (c *Config) readRemotes() {
 
 func (c *Config) simplify() {
 	var mainSection = c.file.Section("")
-	var omitempty = []string{"past_version", "next_version", "last_update_check"}
+	var omitempty = []string{"past_version", "next_version", "last_update_check", "mask_env_patterns", "credentials_store",
+		"session_expiry_warning"}
 
 	for _, k := range omitempty {
 		var key = mainSection.Key(k)
@@ -304,6 +327,15 @@ func (c *Config) simplify() {
 			mainSection.DeleteKey(k)
 		}
 	}
+
+	var omitfalse = []string{"mask_all_env_values"}
+
+	for _, k := range omitfalse {
+		var key = mainSection.Key(k)
+		if (key.Value() == "" || key.Value() == "false") && key.Comment == "" {
+			mainSection.DeleteKey(k)
+		}
+	}
 }
 
 func (c *Config) simplifyRemotes() {
@@ -324,7 +356,7 @@ func (c *Config) simplifyRemotes() {
 			c.deleteRemote(k)
 		}
 
-		var omitempty = []string{"username", "token"}
+		var omitempty = []string{"username", "token", "credential"}
 
 		for _, k := range omitempty {
 			var key = remote.Key(k)
@@ -362,9 +394,19 @@ func (c *Config) updateRemotes() {
 		keyUsername.Comment = v.UsernameComment
 
 		keyToken := remote.Key("token")
-		keyToken.SetValue(v.Token)
 		keyToken.Comment = v.TokenComment
 
+		// tokens are only saved as plaintext when not on the credentials store
+		switch v.Credential {
+		case "":
+			keyToken.SetValue(v.Token)
+		default:
+			keyToken.SetValue("")
+		}
+
+		keyCredential := remote.Key("credential")
+		keyCredential.SetValue(v.Credential)
+
 		remote.Comment = v.Comment
 	}
 
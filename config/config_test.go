@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/henvic/wedeploycli/credentials"
 	"github.com/henvic/wedeploycli/defaults"
 	"github.com/henvic/wedeploycli/remotes"
 	"github.com/henvic/wedeploycli/tdata"
@@ -212,6 +213,10 @@ func TestSave(t *testing.T) {
 	// save in a different location
 	conf.Path = tmp.Name()
 
+	var params = conf.GetParams()
+	params.CredentialsStore = credentials.PlaintextStore
+	conf.SetParams(params)
+
 	if err = conf.Save(); err != nil {
 		panic(err)
 	}
@@ -228,6 +233,7 @@ func TestSave(t *testing.T) {
 		`notify_updates                   = false`,
 		`release_channel                  = stable`,
 		`enable_analytics                 = false`,
+		`credentials_store                = plaintext`,
 		`[remote "lcp"]
     ; Default cloud remote
     infrastructure = liferay.cloud
@@ -3,8 +3,11 @@ package config
 import (
 	"fmt"
 	"net"
+	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/henvic/wedeploycli/color"
 )
 
 // Context structure
@@ -83,13 +86,19 @@ func (c *Context) SetEndpoint(remote string) error {
 	}
 
 	var r = rl.Get(remote)
+	var token, err = conf.token(r)
+
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgHiRed,
+			`Warning: can't read credentials for remote "%v": %v`, remote, err))
+	}
 
 	c.context.Remote = remote
 	c.context.Infrastructure = r.InfrastructureServer()
 	c.context.InfrastructureDomain = getRemoteAddress(r.Infrastructure)
 	c.context.ServiceDomain = r.Service
 	c.context.Username = r.Username
-	c.context.Token = r.Token
+	c.context.Token = token
 	return nil
 }
 
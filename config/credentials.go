@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/credentials"
+	"github.com/henvic/wedeploycli/envs"
+	"github.com/henvic/wedeploycli/remotes"
+)
+
+// CredentialsStore used for new tokens, considering the environment variable override
+func (c *Config) CredentialsStore() string {
+	if name := os.Getenv(envs.CredentialsStore); name != "" {
+		return name
+	}
+
+	return c.GetParams().CredentialsStore
+}
+
+// MigrateCredentials moves the plaintext tokens to the credentials store.
+// If the store is not available, tokens are kept as plaintext.
+func (c *Config) MigrateCredentials() error {
+	if c.CredentialsStore() == credentials.PlaintextStore {
+		return nil
+	}
+
+	if err := c.storeCredentials(); err != nil {
+		var params = c.GetParams()
+		params.CredentialsStore = credentials.PlaintextStore
+		c.SetParams(params)
+
+		return errwrap.Wrapf("{{err}}\nTokens are kept as plaintext: change credentials_store on "+
+			c.Path+" once this is fixed", err)
+	}
+
+	if c.usesMachineKey() {
+		_, _ = fmt.Fprintln(os.Stderr, color.Format(color.FgYellow, "Notice: "+credentials.MachineKeyNotice))
+	}
+
+	return nil
+}
+
+// usesMachineKey checks if any credential is saved on the encrypted file using the machine ID as key
+func (c *Config) usesMachineKey() bool {
+	if !credentials.UsesMachineKey() {
+		return false
+	}
+
+	var rl = c.GetParams().Remotes
+
+	for _, k := range rl.Keys() {
+		if ref, err := credentials.ParseReference(rl.Get(k).Credential); err == nil &&
+			ref.Store == credentials.FileStore {
+			return true
+		}
+	}
+
+	return false
+}
+
+// token of a remote, read from the credentials store if it isn't in memory yet
+func (c *Config) token(e remotes.Entry) (string, error) {
+	if e.Token != "" || e.Credential == "" {
+		return e.Token, nil
+	}
+
+	if token, ok := c.resolved[e.Credential]; ok {
+		return token, nil
+	}
+
+	var ref, err = credentials.ParseReference(e.Credential)
+
+	if err != nil {
+		return "", err
+	}
+
+	store, err := c.store(ref.Store)
+
+	if err != nil {
+		return "", err
+	}
+
+	token, err := store.Get(ref.ID)
+
+	if err != nil {
+		return "", err
+	}
+
+	c.setResolved(e.Credential, token)
+	return token, nil
+}
+
+// storeCredentials saves the tokens on the credentials store, replacing them with references.
+// Tokens that can't be saved on the store are kept as plaintext, and the first error is returned.
+func (c *Config) storeCredentials() error {
+	var params = c.GetParams()
+	var rl = params.Remotes
+
+	if rl == nil {
+		return nil
+	}
+
+	var failed error
+
+	for _, k := range rl.Keys() {
+		var e = rl.Get(k)
+
+		if e.Token == "" {
+			continue
+		}
+
+		if token, ok := c.resolved[e.Credential]; ok && token == e.Token {
+			continue
+		}
+
+		var ref, err = c.storeCredential(e.Credential, e.Token)
+
+		if err != nil && failed == nil {
+			failed = errwrap.Wrapf(`can't save credentials for remote "`+k+`": {{err}}`, err)
+		}
+
+		e.Credential = ref
+		rl.Set(k, e)
+	}
+
+	if err := c.deleteUnusedCredentials(); err != nil && failed == nil {
+		failed = err
+	}
+
+	return failed
+}
+
+func (c *Config) storeCredential(previous, token string) (string, error) {
+	var name = c.CredentialsStore()
+
+	if name == credentials.PlaintextStore {
+		return "", nil
+	}
+
+	var store, err = c.store(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	var ref = credentials.Reference{
+		Store: store.Name(),
+	}
+
+	// reuse the previous ID, unless the credential is moving to another store
+	if p, err := credentials.ParseReference(previous); err == nil && p.Store == ref.Store {
+		ref.ID = p.ID
+	}
+
+	if ref.ID == "" {
+		if ref.ID, err = credentials.NewID(); err != nil {
+			return "", err
+		}
+	}
+
+	if err := store.Set(ref.ID, token); err != nil {
+		return "", err
+	}
+
+	c.addKnownCredential(ref.String())
+	c.setResolved(ref.String(), token)
+	return ref.String(), nil
+}
+
+// deleteUnusedCredentials removes credentials no longer referenced (say, after logging out)
+func (c *Config) deleteUnusedCredentials() error {
+	var rl = c.GetParams().Remotes
+	var used = map[string]bool{}
+
+	for _, k := range rl.Keys() {
+		used[rl.Get(k).Credential] = true
+	}
+
+	for k := range c.known {
+		if used[k] {
+			continue
+		}
+
+		var ref, err = credentials.ParseReference(k)
+
+		if err != nil {
+			delete(c.known, k)
+			continue
+		}
+
+		store, err := c.store(ref.Store)
+
+		if err == nil {
+			err = store.Delete(ref.ID)
+		}
+
+		if err != nil {
+			return errwrap.Wrapf("can't delete unused credentials: {{err}}", err)
+		}
+
+		delete(c.known, k)
+		delete(c.resolved, k)
+	}
+
+	return nil
+}
+
+func (c *Config) store(name string) (credentials.Store, error) {
+	if c.Credentials != nil && (name == "" || name == c.Credentials.Name()) {
+		return c.Credentials, nil
+	}
+
+	if s, ok := c.stores[name]; ok {
+		return s, nil
+	}
+
+	var s, err = credentials.Open(name, filepath.Dir(c.Path))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.stores == nil {
+		c.stores = map[string]credentials.Store{}
+	}
+
+	c.stores[name] = s
+	c.stores[s.Name()] = s
+	return s, nil
+}
+
+func (c *Config) addKnownCredential(ref string) {
+	if ref == "" {
+		return
+	}
+
+	if c.known == nil {
+		c.known = map[string]bool{}
+	}
+
+	c.known[ref] = true
+}
+
+func (c *Config) setResolved(ref, token string) {
+	if c.resolved == nil {
+		c.resolved = map[string]string{}
+	}
+
+	c.resolved[ref] = token
+}
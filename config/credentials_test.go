@@ -0,0 +1,248 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/henvic/wedeploycli/credentials"
+	"github.com/henvic/wedeploycli/credentials/credentialstest"
+	"github.com/henvic/wedeploycli/remotes"
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+// setupCredentials copies a configuration file and the fake store to a temporary directory
+func setupCredentials(t *testing.T, mock string) (Context, *credentialstest.Store) {
+	var dir, err = ioutil.TempDir("", "lcp-config")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	var path = filepath.Join(dir, ".lcp")
+
+	if err := ioutil.WriteFile(path, []byte(tdata.FromFile(mock)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var store = credentialstest.New(filepath.Join(dir, "store.json"))
+
+	if err := ioutil.WriteFile(store.Path, []byte(tdata.FromFile("mocks/credentials/store.json")), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	wectx, err := Setup(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wectx.Config().Credentials = store
+	return wectx, store
+}
+
+func reloadCredentials(t *testing.T, wectx Context, store credentials.Store) Context {
+	var reloaded, err = Setup(wectx.Config().Path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded.Config().Credentials = store
+	return reloaded
+}
+
+func assertToken(t *testing.T, wectx Context, remote, want string) {
+	t.Helper()
+
+	if err := wectx.SetEndpoint(remote); err != nil {
+		t.Fatalf("Expected no error setting endpoint, got %v instead", err)
+	}
+
+	if wectx.Token() != want {
+		t.Errorf("Wanted token for %v to be %v, got %v instead", remote, want, wectx.Token())
+	}
+}
+
+func assertSecrets(t *testing.T, store *credentialstest.Store, want ...string) {
+	t.Helper()
+
+	var m, err = store.Secrets()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got = []string{}
+
+	for _, v := range m {
+		got = append(got, v)
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("Wanted secrets %v, got %v instead", want, got)
+		return
+	}
+
+	for _, w := range want {
+		var found bool
+
+		for _, g := range got {
+			found = found || g == w
+		}
+
+		if !found {
+			t.Errorf("Wanted secrets %v, got %v instead", want, got)
+		}
+	}
+}
+
+func TestCredentialsRead(t *testing.T) {
+	var wectx, _ = setupCredentials(t, "mocks/credentials/.lcp")
+
+	var lcp = wectx.Config().GetParams().Remotes.Get("lcp")
+
+	if lcp.Credential != "fake:abc" || lcp.Token != "" {
+		t.Errorf("Expected only a credential reference to be loaded, got %+v instead", lcp)
+	}
+
+	assertToken(t, wectx, "lcp", "mock_token")
+	assertToken(t, wectx, "xyz", "123")
+}
+
+func TestCredentialsReadFailure(t *testing.T) {
+	var wectx, store = setupCredentials(t, "mocks/credentials/.lcp")
+	store.Err = errors.New("keyring is locked")
+
+	assertToken(t, wectx, "lcp", "")
+}
+
+func TestMigrateCredentials(t *testing.T) {
+	var wectx, store = setupCredentials(t, "mocks/home/.lcp")
+	var conf = wectx.Config()
+
+	if err := conf.MigrateCredentials(); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if err := conf.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var got = tdata.FromFile(conf.Path)
+
+	if strings.Contains(got, "token") || strings.Count(got, "credential     = fake:") != 2 {
+		t.Errorf("Expected tokens to be replaced by references, got %v instead", got)
+	}
+
+	assertSecrets(t, store, "mock_token", "123", "mock_token")
+
+	var reloaded = reloadCredentials(t, wectx, store)
+	assertToken(t, reloaded, "lcp", "mock_token")
+	assertToken(t, reloaded, "xyz", "123")
+}
+
+func TestMigrateCredentialsFailure(t *testing.T) {
+	var wectx, store = setupCredentials(t, "mocks/home/.lcp")
+	var conf = wectx.Config()
+	store.Err = errors.New("keyring is locked")
+
+	if err := conf.MigrateCredentials(); err == nil || !strings.Contains(err.Error(), "keyring is locked") {
+		t.Errorf("Expected migration to fail, got %v instead", err)
+	}
+
+	if got := conf.GetParams().CredentialsStore; got != credentials.PlaintextStore {
+		t.Errorf("Expected credentials store to fall back to plaintext, got %v instead", got)
+	}
+
+	if err := conf.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var got = tdata.FromFile(conf.Path)
+
+	if !strings.Contains(got, "token          = mock_token") || strings.Contains(got, "fake:") {
+		t.Errorf("Expected tokens to be kept as plaintext, got %v instead", got)
+	}
+}
+
+func TestSaveCredentialsStoreUnavailable(t *testing.T) {
+	var wectx, _ = setupCredentials(t, "mocks/credentials/.lcp")
+	var conf = wectx.Config()
+	var params = conf.GetParams()
+
+	// the store can't be opened (say, no machine ID or D-Bus available)
+	conf.Credentials = nil
+	params.CredentialsStore = "unavailable"
+	conf.SetParams(params)
+
+	var lcp = params.Remotes.Get("lcp")
+	lcp.Token = "new_token"
+	params.Remotes.Set("lcp", lcp)
+
+	if err := conf.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var got = tdata.FromFile(conf.Path)
+
+	if !strings.Contains(got, "token          = new_token") || strings.Contains(got, "fake:abc") {
+		t.Errorf("Expected token to be kept as plaintext, got %v instead", got)
+	}
+}
+
+func TestCredentialsUpdateAndDelete(t *testing.T) {
+	var wectx, store = setupCredentials(t, "mocks/credentials/.lcp")
+	var conf = wectx.Config()
+	var rl = conf.GetParams().Remotes
+
+	// login again
+	var lcp = rl.Get("lcp")
+	lcp.Token = "new_token"
+	rl.Set("lcp", lcp)
+
+	if err := conf.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if got := rl.Get("lcp").Credential; got != "fake:abc" {
+		t.Errorf("Expected credential reference to be kept, got %v instead", got)
+	}
+
+	var xyz = rl.Get("xyz")
+	assertSecrets(t, store, "new_token", "123")
+
+	// logout
+	lcp = rl.Get("lcp")
+	lcp.Token = ""
+	lcp.Credential = ""
+	rl.Set("lcp", lcp)
+
+	if err := conf.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	assertSecrets(t, store, "123")
+
+	var reloaded = reloadCredentials(t, wectx, store)
+	var want = remotes.Entry{
+		Infrastructure: "wedeploy.xyz",
+		Username:       "foobar@example.net",
+		Credential:     xyz.Credential,
+	}
+
+	if got := reloaded.Config().GetParams().Remotes.Get("xyz"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+
+	assertToken(t, reloaded, "lcp", "")
+	assertToken(t, reloaded, "xyz", "123")
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/envs"
+	"github.com/henvic/wedeploycli/usertoken"
+)
+
+// SessionExpiryWarning is how long before the session expires a warning is shown,
+// considering the environment variable override.
+// If the value is invalid, the default is returned along with the error.
+func (c *Config) SessionExpiryWarning() (time.Duration, error) {
+	var value = c.GetParams().SessionExpiry
+
+	if v := os.Getenv(envs.SessionExpiryWarning); v != "" {
+		value = v
+	}
+
+	if value == "" {
+		return usertoken.ExpiryWarning, nil
+	}
+
+	var d, err = time.ParseDuration(value)
+
+	if err == nil && d < 0 {
+		err = errors.New("duration can't be negative")
+	}
+
+	if err != nil {
+		return usertoken.ExpiryWarning, errwrap.Wrapf("invalid session_expiry_warning value: {{err}}", err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/envs"
+	"github.com/henvic/wedeploycli/usertoken"
+)
+
+func TestSessionExpiryWarning(t *testing.T) {
+	var cases = []struct {
+		param string
+		env   string
+		want  time.Duration
+		err   bool
+	}{
+		{want: usertoken.ExpiryWarning},
+		{param: "72h", want: 72 * time.Hour},
+		{param: "72h", env: "30m", want: 30 * time.Minute},
+		{param: "0", want: 0},
+		{param: "tomorrow", want: usertoken.ExpiryWarning, err: true},
+		{env: "-1h", want: usertoken.ExpiryWarning, err: true},
+	}
+
+	defer func() {
+		_ = os.Unsetenv(envs.SessionExpiryWarning)
+	}()
+
+	for _, c := range cases {
+		var conf = &Config{
+			Params: Params{
+				SessionExpiry: c.param,
+			},
+		}
+
+		if err := os.Setenv(envs.SessionExpiryWarning, c.env); err != nil {
+			t.Fatal(err)
+		}
+
+		var got, err = conf.SessionExpiryWarning()
+
+		if got != c.want || (err != nil) != c.err {
+			t.Errorf("Wanted %v (error: %v) for %+v, got %v (error: %v) instead", c.want, c.err, c, got, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package credentials stores tokens outside of the configuration file.
+//
+// The configuration file only keeps a reference to the stored credential,
+// so that it doesn't leak tokens when copied around (say, on home directory backups).
+package credentials
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+)
+
+const (
+	// KeyringStore keeps credentials on the OS keyring (Secret Service over D-Bus)
+	KeyringStore = "keyring"
+
+	// FileStore keeps credentials on an encrypted file
+	FileStore = "file"
+
+	// PlaintextStore keeps tokens on the configuration file as plaintext (not recommended)
+	PlaintextStore = "plaintext"
+)
+
+// ErrNotFound is used when a credential is not found on a store
+var ErrNotFound = errors.New("credential not found")
+
+// Store of credentials
+type Store interface {
+	// Name of the store, used on references
+	Name() string
+
+	// Get a secret
+	Get(id string) (string, error)
+
+	// Set a secret
+	Set(id, secret string) error
+
+	// Delete a secret (not finding it is not an error)
+	Delete(id string) error
+}
+
+// Reference to a credential on a store
+type Reference struct {
+	Store string
+	ID    string
+}
+
+// String returns the reference in the store:id format
+func (r Reference) String() string {
+	return r.Store + ":" + r.ID
+}
+
+// ParseReference in the store:id format
+func ParseReference(s string) (r Reference, err error) {
+	var parts = strings.SplitN(s, ":", 2)
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return r, fmt.Errorf(`invalid credential reference "%s"`, s)
+	}
+
+	return Reference{
+		Store: parts[0],
+		ID:    parts[1],
+	}, nil
+}
+
+// NewID for a credential
+func NewID() (string, error) {
+	var b = make([]byte, 8)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", errwrap.Wrapf("can't generate credential ID: {{err}}", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Open a store by name, keeping files on the given directory.
+// If no name is given, the keyring is used when available, falling back to the encrypted file.
+func Open(name, dir string) (Store, error) {
+	switch name {
+	case KeyringStore:
+		return &SecretService{}, nil
+	case FileStore:
+		return &EncryptedFile{
+			Path:       filepath.Join(dir, Filename),
+			Passphrase: DefaultPassphrase,
+			KDF:        DefaultFileKDF(),
+		}, nil
+	case "":
+		if SecretServiceAvailable() {
+			return Open(KeyringStore, dir)
+		}
+
+		return Open(FileStore, dir)
+	}
+
+	return nil, fmt.Errorf(`unknown credentials store "%s" (use %s, %s, or %s)`,
+		name, KeyringStore, FileStore, PlaintextStore)
+}
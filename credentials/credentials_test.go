@@ -0,0 +1,305 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	var dir, err = ioutil.TempDir("", "lcp-credentials")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+func newTestFile(path, passphrase string) *EncryptedFile {
+	return &EncryptedFile{
+		Path: path,
+		Passphrase: func() ([]byte, error) {
+			return []byte(passphrase), nil
+		},
+	}
+}
+
+func init() {
+	// cheaper key derivation for tests
+	DefaultKDF.N = 1 << 10
+}
+
+func TestParseReference(t *testing.T) {
+	var r, err = ParseReference("keyring:abc:def")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if r.Store != "keyring" || r.ID != "abc:def" || r.String() != "keyring:abc:def" {
+		t.Errorf("Unexpected reference: %+v", r)
+	}
+
+	for _, s := range []string{"", "keyring", "keyring:", ":abc"} {
+		if _, err := ParseReference(s); err == nil {
+			t.Errorf("Expected error parsing %q, got nil instead", s)
+		}
+	}
+}
+
+func TestNewID(t *testing.T) {
+	a, errA := NewID()
+	b, errB := NewID()
+
+	if errA != nil || errB != nil || len(a) != 16 || a == b {
+		t.Errorf("Expected two different IDs, got %v (%v) and %v (%v) instead", a, errA, b, errB)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	var s, err = Open(FileStore, "/home/foo")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if f, ok := s.(*EncryptedFile); !ok || f.Path != filepath.Join("/home/foo", Filename) {
+		t.Errorf("Expected encrypted file store, got %+v instead", s)
+	}
+
+	if s, err := Open(KeyringStore, ""); err != nil || s.Name() != KeyringStore {
+		t.Errorf("Expected keyring store, got %v (error: %v) instead", s, err)
+	}
+
+	if _, err := Open("unknown", ""); err == nil {
+		t.Errorf("Expected error opening unknown store, got nil instead")
+	}
+}
+
+func TestEncryptedFile(t *testing.T) {
+	var path = filepath.Join(tempDir(t), Filename)
+	var f = newTestFile(path, "passphrase")
+
+	if _, err := f.Get("a"); err != ErrNotFound {
+		t.Errorf("Expected %v, got %v instead", ErrNotFound, err)
+	}
+
+	if err := f.Set("a", "token-a"); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if err := f.Set("b", "token-b"); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if err := f.Set("a", "token-a2"); err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var b, err = ioutil.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "token-") {
+		t.Errorf("Expected credentials file to be encrypted, got %s instead", b)
+	}
+
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected credentials file with mode 0600, got %v (error: %v) instead", fi.Mode(), err)
+	}
+
+	// read with a new instance, to avoid using the cached key
+	var other = newTestFile(path, "passphrase")
+
+	if got, err := other.Get("a"); got != "token-a2" || err != nil {
+		t.Errorf("Expected token-a2, got %v (error: %v) instead", got, err)
+	}
+
+	if err := other.Delete("a"); err != nil {
+		t.Errorf("Expected no error deleting, got %v instead", err)
+	}
+
+	if err := other.Delete("a"); err != nil {
+		t.Errorf("Expected no error deleting missing credential, got %v instead", err)
+	}
+
+	if _, err := f.Get("a"); err != ErrNotFound {
+		t.Errorf("Expected %v, got %v instead", ErrNotFound, err)
+	}
+
+	if got, err := f.Get("b"); got != "token-b" || err != nil {
+		t.Errorf("Expected token-b, got %v (error: %v) instead", got, err)
+	}
+}
+
+func TestEncryptedFileWrongPassphrase(t *testing.T) {
+	var path = filepath.Join(tempDir(t), Filename)
+
+	if err := newTestFile(path, "passphrase").Set("a", "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newTestFile(path, "wrong").Get("a"); err != ErrPassphraseMismatch {
+		t.Errorf("Expected %v, got %v instead", ErrPassphraseMismatch, err)
+	}
+
+	if err := newTestFile(path, "wrong").Set("b", "other"); err != ErrPassphraseMismatch {
+		t.Errorf("Expected %v, got %v instead", ErrPassphraseMismatch, err)
+	}
+
+	// the check value is kept after removing all credentials
+	if err := newTestFile(path, "passphrase").Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newTestFile(path, "wrong").Set("b", "other"); err != ErrPassphraseMismatch {
+		t.Errorf("Expected %v on file without credentials, got %v instead", ErrPassphraseMismatch, err)
+	}
+}
+
+func TestEncryptedFileWithoutCheck(t *testing.T) {
+	var path = filepath.Join(tempDir(t), Filename)
+	var f = newTestFile(path, "passphrase")
+
+	if err := f.Set("a", "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	var c, err = f.read()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Check = ""
+
+	if err := f.write(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := newTestFile(path, "wrong").Set("b", "other"); err != ErrPassphraseMismatch {
+		t.Errorf("Expected %v, got %v instead", ErrPassphraseMismatch, err)
+	}
+
+	if err := newTestFile(path, "passphrase").Set("b", "other"); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if c, err = f.read(); err != nil || c.Check == "" {
+		t.Errorf("Expected check value to be added, got %v (error: %v) instead", c.Check, err)
+	}
+}
+
+func TestEncryptedFileSwappedValues(t *testing.T) {
+	var path = filepath.Join(tempDir(t), Filename)
+	var f = newTestFile(path, "passphrase")
+
+	if err := f.Set("a", "token-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Set("b", "token-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	var b, err = ioutil.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c fileContent
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Credentials["a"], c.Credentials["b"] = c.Credentials["b"], c.Credentials["a"]
+
+	if err := f.write(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Get("a"); err != ErrWrongPassphrase {
+		t.Errorf("Expected swapped values to fail with %v, got %v instead", ErrWrongPassphrase, err)
+	}
+}
+
+func TestEncryptedFileInvalidKDF(t *testing.T) {
+	var cases = []KDF{
+		{Name: "pbkdf2", N: 1 << 10, R: 8, P: 1},
+		{Name: "scrypt", N: 2, R: 8, P: 1},
+		{Name: "scrypt", N: 1 << 30, R: 8, P: 1},
+		{Name: "scrypt", N: 1<<10 + 1, R: 8, P: 1},
+		{Name: "scrypt", N: 1 << 10, R: 1, P: 1},
+		{Name: "scrypt", N: 1 << 10, R: 1 << 20, P: 1},
+		{Name: "scrypt", N: 1 << 10, R: 8, P: 0},
+		{Name: "scrypt", N: 1 << 10, R: 8, P: 1 << 20},
+	}
+
+	for _, k := range cases {
+		var path = filepath.Join(tempDir(t), Filename)
+		var f = newTestFile(path, "passphrase")
+
+		if err := f.Set("a", "token"); err != nil {
+			t.Fatal(err)
+		}
+
+		var c, err = f.read()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		k.Salt = c.KDF.Salt
+		c.KDF = k
+
+		if err := f.write(c); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := newTestFile(path, "passphrase").Get("a"); err == nil {
+			t.Errorf("Expected error for parameters %+v, got nil instead", k)
+		}
+
+		if err := newTestFile(path, "passphrase").Set("b", "token"); err == nil {
+			t.Errorf("Expected error saving with parameters %+v, got nil instead", k)
+		}
+	}
+}
+
+func TestEncryptedFilePassphraseSetLater(t *testing.T) {
+	var path = filepath.Join(tempDir(t), Filename)
+	var f = newTestFile(path, "machine-id")
+	f.KDF = MachineKDF
+
+	if err := f.Set("a", "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	var other = newTestFile(path, "passphrase")
+	other.KDF = DefaultKDF
+	other.KDF.N = 1 << 11
+
+	if _, err := other.Get("a"); err != ErrPassphraseMismatch {
+		t.Errorf("Expected %v, got %v instead", ErrPassphraseMismatch, err)
+	}
+
+	if err := other.Set("b", "other"); err != ErrPassphraseMismatch {
+		t.Errorf("Expected %v, got %v instead", ErrPassphraseMismatch, err)
+	}
+
+	if got, err := newTestFile(path, "machine-id").Get("a"); got != "token" || err != nil {
+		t.Errorf("Expected token, got %v (error: %v) instead", got, err)
+	}
+}
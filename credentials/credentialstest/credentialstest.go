@@ -0,0 +1,115 @@
+// Package credentialstest provides a file-backed fake credentials store for tests.
+package credentialstest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/henvic/wedeploycli/credentials"
+)
+
+// Name of the fake store, used on references
+const Name = "fake"
+
+// Store of credentials saved as plaintext JSON on a file
+type Store struct {
+	Path string
+
+	// Err is returned by all methods, if not nil
+	Err error
+
+	m sync.Mutex
+}
+
+// New fake store on the given path
+func New(path string) *Store {
+	return &Store{
+		Path: path,
+	}
+}
+
+// Name of the store
+func (s *Store) Name() string {
+	return Name
+}
+
+// Get a secret
+func (s *Store) Get(id string) (string, error) {
+	var m, err = s.read()
+
+	if err != nil {
+		return "", err
+	}
+
+	if v, ok := m[id]; ok {
+		return v, nil
+	}
+
+	return "", credentials.ErrNotFound
+}
+
+// Set a secret
+func (s *Store) Set(id, secret string) error {
+	var m, err = s.read()
+
+	if err != nil {
+		return err
+	}
+
+	m[id] = secret
+	return s.write(m)
+}
+
+// Delete a secret
+func (s *Store) Delete(id string) error {
+	var m, err = s.read()
+
+	if err != nil {
+		return err
+	}
+
+	delete(m, id)
+	return s.write(m)
+}
+
+// Secrets saved on the store
+func (s *Store) Secrets() (map[string]string, error) {
+	return s.read()
+}
+
+func (s *Store) read() (map[string]string, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	var m = map[string]string{}
+	var b, err = ioutil.ReadFile(s.Path)
+
+	switch {
+	case os.IsNotExist(err):
+		return m, nil
+	case err != nil:
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+func (s *Store) write(m map[string]string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var b, err = json.MarshalIndent(m, "", "    ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.Path, b, 0600)
+}
@@ -0,0 +1,428 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/envs"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Filename of the encrypted credentials file
+const Filename = ".lcp_credentials"
+
+// Version of the file format
+const Version = 1
+
+const (
+	keyLength = 32
+	saltSize  = 16
+
+	// checkID and checkValue are used for verifying the key, even if there are no credentials
+	checkID    = "="
+	checkValue = "lcp"
+)
+
+// Bounds for the scrypt parameters read from files, to avoid having to allocate too much memory,
+// or using keys weaker than the ones of new files.
+const (
+	minN = 1 << 10
+	maxN = 1 << 18
+	minR = 8
+	maxR = 16
+	maxP = 4
+)
+
+// MachineKeyNotice explains what the encrypted file protects against when the machine ID is used as its key
+const MachineKeyNotice = `Credentials saved on the ` + Filename + ` file are encrypted with a key derived from
+this machine's ID, unless ` + envs.CredentialsPassphrase + ` is set. This keeps the file
+useless if copied alone, but isn't a secret: other users on this machine and full-disk
+backups can read the machine ID. Set ` + envs.CredentialsPassphrase + ` for a passphrase,
+or use an OS keyring (Secret Service), to protect them.`
+
+// ErrWrongPassphrase is used when credentials can't be decrypted
+var ErrWrongPassphrase = errors.New("can't decrypt credentials: wrong key or corrupted file")
+
+// ErrPassphraseMismatch is used when the key doesn't match the one used on the credentials file
+var ErrPassphraseMismatch = errors.New("passphrase doesn't match the credentials file (was " +
+	envs.CredentialsPassphrase + " set or changed after it was created?)")
+
+// KDF parameters for deriving the encryption key
+type KDF struct {
+	Name string `json:"name"`
+	Salt string `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// DefaultKDF parameters (see scrypt recommendations for interactive logins)
+var DefaultKDF = KDF{
+	Name: "scrypt",
+	N:    1 << 15,
+	R:    8,
+	P:    1,
+}
+
+// MachineKDF parameters, used with the machine ID.
+// It has enough entropy already, so there is no need to slow down every command with a costly derivation.
+var MachineKDF = KDF{
+	Name: "scrypt",
+	N:    1 << 10,
+	R:    8,
+	P:    1,
+}
+
+type fileContent struct {
+	Version     int               `json:"version"`
+	KDF         KDF               `json:"kdf"`
+	Check       string            `json:"check,omitempty"`
+	Credentials map[string]string `json:"credentials"`
+}
+
+// EncryptedFile store, using AES-256-GCM with a key derived from a passphrase with scrypt.
+// Each credential is encrypted separately, using its ID as additional authenticated data.
+type EncryptedFile struct {
+	Path string
+
+	// Passphrase for deriving the encryption key
+	Passphrase func() ([]byte, error)
+
+	// KDF parameters for new files (defaults to DefaultKDF)
+	KDF KDF
+
+	m    sync.Mutex
+	salt string
+	aead cipher.AEAD
+}
+
+// UsesMachineKey tells if the DefaultPassphrase is the machine ID
+func UsesMachineKey() bool {
+	var p, ok = os.LookupEnv(envs.CredentialsPassphrase)
+	return !ok || p == ""
+}
+
+// DefaultPassphrase is the machine ID, unless a passphrase is set on the environment.
+// The machine ID is not stored on the home directory, so the credentials file is useless
+// if copied alone. However, it is readable by any local user and often part of full-disk
+// backups, so it only obfuscates the credentials (see MachineKeyNotice).
+func DefaultPassphrase() ([]byte, error) {
+	if !UsesMachineKey() {
+		return []byte(os.Getenv(envs.CredentialsPassphrase)), nil
+	}
+
+	var id, err = machineID()
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't get machine ID to encrypt credentials (set "+
+			envs.CredentialsPassphrase+" instead): {{err}}", err)
+	}
+
+	return id, nil
+}
+
+// DefaultFileKDF returns the KDF parameters for the DefaultPassphrase
+func DefaultFileKDF() KDF {
+	if UsesMachineKey() {
+		return MachineKDF
+	}
+
+	return DefaultKDF
+}
+
+// Name of the store
+func (f *EncryptedFile) Name() string {
+	return FileStore
+}
+
+// Get a secret
+func (f *EncryptedFile) Get(id string) (string, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	var c, err = f.read()
+
+	if err != nil {
+		return "", err
+	}
+
+	var sealed, ok = c.Credentials[id]
+
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	aead, err := f.cipher(c.KDF)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := verify(aead, c); err != nil {
+		return "", err
+	}
+
+	return open(aead, id, sealed)
+}
+
+// Set a secret
+func (f *EncryptedFile) Set(id, secret string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	var c, err = f.read()
+
+	if err != nil {
+		return err
+	}
+
+	aead, err := f.cipher(c.KDF)
+
+	if err != nil {
+		return err
+	}
+
+	// avoid saving values sealed with different keys on the same file
+	if err := verify(aead, c); err != nil {
+		return err
+	}
+
+	if c.Check == "" {
+		if c.Check, err = seal(aead, checkID, checkValue); err != nil {
+			return err
+		}
+	}
+
+	if c.Credentials[id], err = seal(aead, id, secret); err != nil {
+		return err
+	}
+
+	return f.write(c)
+}
+
+// Delete a secret
+func (f *EncryptedFile) Delete(id string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	var c, err = f.read()
+
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c.Credentials[id]; !ok {
+		return nil
+	}
+
+	delete(c.Credentials, id)
+	return f.write(c)
+}
+
+func (f *EncryptedFile) read() (*fileContent, error) {
+	var b, err = ioutil.ReadFile(f.Path)
+
+	if os.IsNotExist(err) {
+		return f.newFileContent()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var c fileContent
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errwrap.Wrapf("can't parse credentials file: {{err}}", err)
+	}
+
+	if c.Version != Version {
+		return nil, fmt.Errorf("unsupported credentials file version %d", c.Version)
+	}
+
+	if err := f.validate(c.KDF); err != nil {
+		return nil, err
+	}
+
+	if c.Credentials == nil {
+		c.Credentials = map[string]string{}
+	}
+
+	return &c, nil
+}
+
+func (f *EncryptedFile) validate(kdf KDF) error {
+	if kdf.Name != DefaultKDF.Name {
+		return fmt.Errorf(`unsupported key derivation function "%s"`, kdf.Name)
+	}
+
+	if kdf.N < minN || kdf.N > maxN || kdf.N&(kdf.N-1) != 0 ||
+		kdf.R < minR || kdf.R > maxR ||
+		kdf.P < 1 || kdf.P > maxP {
+		return fmt.Errorf("invalid key derivation parameters on credentials file (N=%d, r=%d, p=%d)",
+			kdf.N, kdf.R, kdf.P)
+	}
+
+	return nil
+}
+
+func (f *EncryptedFile) newFileContent() (*fileContent, error) {
+	var c = &fileContent{
+		Version:     Version,
+		KDF:         f.KDF,
+		Credentials: map[string]string{},
+	}
+
+	if c.KDF.Name == "" {
+		c.KDF = DefaultKDF
+	}
+
+	var salt = make([]byte, saltSize)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errwrap.Wrapf("can't generate salt: {{err}}", err)
+	}
+
+	c.KDF.Salt = base64.StdEncoding.EncodeToString(salt)
+	return c, nil
+}
+
+func (f *EncryptedFile) write(c *fileContent) error {
+	var b, err = json.MarshalIndent(c, "", "    ")
+
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first to avoid losing credentials on failures
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp")
+
+	if err != nil {
+		return errwrap.Wrapf("can't save credentials: {{err}}", err)
+	}
+
+	_, err = tmp.Write(append(b, '\n'))
+
+	if errc := tmp.Close(); err == nil {
+		err = errc
+	}
+
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0600)
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.Path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return errwrap.Wrapf("can't save credentials: {{err}}", err)
+	}
+
+	return nil
+}
+
+// cipher derives the key only once for each salt, as it is expensive by design
+func (f *EncryptedFile) cipher(kdf KDF) (cipher.AEAD, error) {
+	if f.aead != nil && f.salt == kdf.Salt {
+		return f.aead, nil
+	}
+
+	if f.Passphrase == nil {
+		return nil, errors.New("no passphrase for the credentials file")
+	}
+
+	passphrase, err := f.Passphrase()
+
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(kdf.Salt)
+
+	if err != nil {
+		return nil, errwrap.Wrapf("invalid salt: {{err}}", err)
+	}
+
+	key, err := scrypt.Key(passphrase, salt, kdf.N, kdf.R, kdf.P, keyLength)
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't derive key: {{err}}", err)
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f.aead, f.salt = aead, kdf.Salt
+	return aead, nil
+}
+
+// verify the key with the check value, or with a credential on files saved without it
+func verify(aead cipher.AEAD, c *fileContent) error {
+	if c.Check != "" {
+		if v, err := open(aead, checkID, c.Check); err != nil || v != checkValue {
+			return ErrPassphraseMismatch
+		}
+
+		return nil
+	}
+
+	for id, sealed := range c.Credentials {
+		if _, err := open(aead, id, sealed); err != nil {
+			return ErrPassphraseMismatch
+		}
+
+		break
+	}
+
+	return nil
+}
+
+func seal(aead cipher.AEAD, id, value string) (string, error) {
+	var nonce = make([]byte, aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errwrap.Wrapf("can't generate nonce: {{err}}", err)
+	}
+
+	var sealed = aead.Seal(nonce, nonce, []byte(value), []byte(id))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func open(aead cipher.AEAD, id, value string) (string, error) {
+	var b, err = base64.StdEncoding.DecodeString(value)
+
+	if err != nil || len(b) < aead.NonceSize() {
+		return "", ErrWrongPassphrase
+	}
+
+	var nonce, ciphertext = b[:aead.NonceSize()], b[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+
+	return string(plain), nil
+}
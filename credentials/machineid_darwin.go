@@ -0,0 +1,27 @@
+// +build darwin
+
+package credentials
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+)
+
+var platformUUIDRegex = regexp.MustCompile(`"IOPlatformUUID" = "([^"]+)"`)
+
+func machineID() ([]byte, error) {
+	var out, err = exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m = platformUUIDRegex.FindSubmatch(out)
+
+	if m == nil {
+		return nil, errors.New("IOPlatformUUID not found")
+	}
+
+	return m[1], nil
+}
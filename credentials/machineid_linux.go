@@ -0,0 +1,26 @@
+// +build linux
+
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+)
+
+var machineIDFiles = []string{
+	"/etc/machine-id",
+	"/var/lib/dbus/machine-id",
+}
+
+func machineID() ([]byte, error) {
+	for _, f := range machineIDFiles {
+		var b, err = ioutil.ReadFile(f) // #nosec
+
+		if b = bytes.TrimSpace(b); err == nil && len(b) != 0 {
+			return b, nil
+		}
+	}
+
+	return nil, errors.New("machine-id not found")
+}
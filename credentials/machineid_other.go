@@ -0,0 +1,9 @@
+// +build !linux,!darwin,!windows
+
+package credentials
+
+import "errors"
+
+func machineID() ([]byte, error) {
+	return nil, errors.New("machine ID is not available on this platform")
+}
@@ -0,0 +1,28 @@
+// +build windows
+
+package credentials
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+)
+
+var machineGUIDRegex = regexp.MustCompile(`MachineGuid\s+REG_SZ\s+(\S+)`)
+
+func machineID() ([]byte, error) {
+	var out, err = exec.Command("reg", "query",
+		`HKLM\SOFTWARE\Microsoft\Cryptography`, "/v", "MachineGuid").Output()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m = machineGUIDRegex.FindSubmatch(out)
+
+	if m == nil {
+		return nil, errors.New("MachineGuid not found")
+	}
+
+	return m[1], nil
+}
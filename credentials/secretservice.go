@@ -0,0 +1,289 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/hashicorp/errwrap"
+)
+
+// See https://specifications.freedesktop.org/secret-service/latest/
+const (
+	secretServiceName       = "org.freedesktop.secrets"
+	secretServicePath       = "/org/freedesktop/secrets"
+	secretServiceCollection = "/org/freedesktop/secrets/aliases/default"
+
+	secretServiceInterface    = "org.freedesktop.Secret.Service"
+	secretCollectionInterface = "org.freedesktop.Secret.Collection"
+	secretItemInterface       = "org.freedesktop.Secret.Item"
+	secretSessionInterface    = "org.freedesktop.Secret.Session"
+	secretPromptInterface     = "org.freedesktop.Secret.Prompt"
+
+	// noPrompt is returned by methods that don't need a prompt
+	noPrompt = dbus.ObjectPath("/")
+)
+
+// Application name used to label the keyring items
+const Application = "lcp"
+
+// secret as defined by the Secret Service API
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// SecretService store, using the OS keyring (GNOME Keyring, KWallet, KeePassXC, and so on) over D-Bus
+type SecretService struct {
+	conn *dbus.Conn
+}
+
+// SecretServiceAvailable checks if there is a Secret Service on the session bus
+func SecretServiceAvailable() bool {
+	// avoid auto-launching a session bus on headless machines
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+
+	var conn, err = dbus.SessionBus()
+
+	if err != nil {
+		return false
+	}
+
+	var has bool
+
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, secretServiceName).Store(&has); err == nil && has {
+		return true
+	}
+
+	var activatable []string
+
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListActivatableNames", 0).Store(&activatable); err != nil {
+		return false
+	}
+
+	for _, name := range activatable {
+		if name == secretServiceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Name of the store
+func (s *SecretService) Name() string {
+	return KeyringStore
+}
+
+// Get a secret
+func (s *SecretService) Get(id string) (string, error) {
+	var items, err = s.search(id)
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(items) == 0 {
+		return "", ErrNotFound
+	}
+
+	session, err := s.openSession()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer s.closeSession(session)
+
+	var sec secret
+
+	if err := s.conn.Object(secretServiceName, items[0]).Call(
+		secretItemInterface+".GetSecret", 0, session).Store(&sec); err != nil {
+		return "", errwrap.Wrapf("can't read secret from keyring: {{err}}", err)
+	}
+
+	return string(sec.Value), nil
+}
+
+// Set a secret
+func (s *SecretService) Set(id, value string) error {
+	if err := s.connect(); err != nil {
+		return err
+	}
+
+	if err := s.unlock([]dbus.ObjectPath{secretServiceCollection}); err != nil {
+		return err
+	}
+
+	session, err := s.openSession()
+
+	if err != nil {
+		return err
+	}
+
+	defer s.closeSession(session)
+
+	var properties = map[string]dbus.Variant{
+		secretItemInterface + ".Label":      dbus.MakeVariant("Liferay Cloud credential (" + Application + ")"),
+		secretItemInterface + ".Attributes": dbus.MakeVariant(attributes(id)),
+	}
+
+	var sec = secret{
+		Session:     session,
+		Value:       []byte(value),
+		ContentType: "text/plain",
+	}
+
+	var item, prompt dbus.ObjectPath
+
+	if err := s.conn.Object(secretServiceName, secretServiceCollection).Call(
+		secretCollectionInterface+".CreateItem", 0, properties, sec, true).Store(&item, &prompt); err != nil {
+		return errwrap.Wrapf("can't save secret on keyring: {{err}}", err)
+	}
+
+	return s.prompt(prompt)
+}
+
+// Delete a secret
+func (s *SecretService) Delete(id string) error {
+	var items, err = s.search(id)
+
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		var prompt dbus.ObjectPath
+
+		if err := s.conn.Object(secretServiceName, item).Call(
+			secretItemInterface+".Delete", 0).Store(&prompt); err != nil {
+			return errwrap.Wrapf("can't delete secret from keyring: {{err}}", err)
+		}
+
+		if err := s.prompt(prompt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func attributes(id string) map[string]string {
+	return map[string]string{
+		"application": Application,
+		"credential":  id,
+	}
+}
+
+func (s *SecretService) connect() (err error) {
+	if s.conn != nil {
+		return nil
+	}
+
+	if s.conn, err = dbus.SessionBus(); err != nil {
+		return errwrap.Wrapf("can't connect to the keyring: {{err}}", err)
+	}
+
+	return nil
+}
+
+func (s *SecretService) service() dbus.BusObject {
+	return s.conn.Object(secretServiceName, secretServicePath)
+}
+
+// search for the items of a credential, unlocking them if necessary
+func (s *SecretService) search(id string) ([]dbus.ObjectPath, error) {
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	var unlocked, locked []dbus.ObjectPath
+
+	if err := s.service().Call(secretServiceInterface+".SearchItems", 0, attributes(id)).Store(&unlocked, &locked); err != nil {
+		return nil, errwrap.Wrapf("can't search keyring: {{err}}", err)
+	}
+
+	if err := s.unlock(locked); err != nil {
+		return nil, err
+	}
+
+	return append(unlocked, locked...), nil
+}
+
+func (s *SecretService) unlock(objects []dbus.ObjectPath) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+
+	if err := s.service().Call(secretServiceInterface+".Unlock", 0, objects).Store(&unlocked, &prompt); err != nil {
+		return errwrap.Wrapf("can't unlock keyring: {{err}}", err)
+	}
+
+	return s.prompt(prompt)
+}
+
+// prompt the user (say, for unlocking the keyring) and wait for it to complete
+func (s *SecretService) prompt(prompt dbus.ObjectPath) error {
+	if prompt == noPrompt || prompt == "" {
+		return nil
+	}
+
+	var match = []dbus.MatchOption{
+		dbus.WithMatchObjectPath(prompt),
+		dbus.WithMatchInterface(secretPromptInterface),
+		dbus.WithMatchMember("Completed"),
+	}
+
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = s.conn.RemoveMatchSignal(match...)
+	}()
+
+	var signals = make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretServiceName, prompt).Call(secretPromptInterface+".Prompt", 0, "").Err; err != nil {
+		return errwrap.Wrapf("can't prompt to unlock keyring: {{err}}", err)
+	}
+
+	for signal := range signals {
+		if signal.Path != prompt || len(signal.Body) == 0 {
+			continue
+		}
+
+		if dismissed, _ := signal.Body[0].(bool); dismissed {
+			return errors.New("keyring prompt dismissed")
+		}
+
+		return nil
+	}
+
+	return errors.New("keyring connection closed")
+}
+
+func (s *SecretService) openSession() (dbus.ObjectPath, error) {
+	var output dbus.Variant
+	var session dbus.ObjectPath
+
+	// the session bus is local, so secrets are transferred unencrypted (like other clients do)
+	if err := s.service().Call(secretServiceInterface+".OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &session); err != nil {
+		return "", errwrap.Wrapf("can't open keyring session: {{err}}", err)
+	}
+
+	return session, nil
+}
+
+func (s *SecretService) closeSession(session dbus.ObjectPath) {
+	_ = s.conn.Object(secretServiceName, session).Call(secretSessionInterface+".Close", 0).Err
+}
@@ -0,0 +1,319 @@
+package domaincheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+)
+
+// DefaultTimeout for each DNS lookup and TLS connection
+const DefaultTimeout = 10 * time.Second
+
+// ExpiryWarning is how long before the certificate expires a warning is shown
+var ExpiryWarning = 14 * 24 * time.Hour
+
+// Checker for custom domains
+type Checker struct {
+	// DNSServer (host:port) is used instead of the local resolver when not empty
+	DNSServer string
+
+	// Dial is used to connect to the domain to fetch its TLS certificate
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// RootCAs used to verify certificates (system roots are used when nil)
+	RootCAs *x509.CertPool
+
+	Timeout time.Duration
+}
+
+// DNS resolution of a custom domain
+type DNS struct {
+	Expected          string
+	CNAME             string
+	Addresses         []string
+	ExpectedAddresses []string
+	Err               error
+}
+
+// OK returns true if the domain points to the expected service endpoint
+func (d DNS) OK() bool {
+	if d.Err != nil {
+		return false
+	}
+
+	if sameHost(d.CNAME, d.Expected) {
+		return true
+	}
+
+	for _, a := range d.Addresses {
+		for _, e := range d.ExpectedAddresses {
+			if a == e {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Certificate served for a custom domain
+type Certificate struct {
+	Issuer    string
+	Subject   string
+	SANs      []string
+	NotBefore time.Time
+	NotAfter  time.Time
+	ExpiresIn time.Duration
+
+	// VerifyErr is set when the certificate isn't trusted for the domain
+	VerifyErr error
+}
+
+// Result of checking a custom domain
+type Result struct {
+	Domain      string
+	DNS         DNS
+	Certificate *Certificate
+	TLSErr      error
+}
+
+// Problems found for the domain
+func (r Result) Problems() []string {
+	var p []string
+
+	switch {
+	case r.DNS.Err != nil:
+		p = append(p, fmt.Sprintf("can't resolve %s: %v", r.Domain, r.DNS.Err))
+	case !r.DNS.OK():
+		p = append(p, fmt.Sprintf("%s doesn't point to %s", r.Domain, r.DNS.Expected))
+	}
+
+	if r.TLSErr != nil {
+		p = append(p, fmt.Sprintf("can't get TLS certificate: %v", r.TLSErr))
+	}
+
+	if r.Certificate == nil {
+		return p
+	}
+
+	var c = r.Certificate
+
+	switch {
+	case c.ExpiresIn <= 0:
+		p = append(p, "certificate expired")
+	case c.ExpiresIn <= ExpiryWarning:
+		p = append(p, fmt.Sprintf("certificate expires in %s", days(c.ExpiresIn)))
+	}
+
+	if c.VerifyErr != nil {
+		p = append(p, fmt.Sprintf("certificate not valid: %v", c.VerifyErr))
+	}
+
+	return p
+}
+
+// OK returns true if no problems were found
+func (r Result) OK() bool {
+	return len(r.Problems()) == 0
+}
+
+// Check domains against the expected service endpoint
+func (c *Checker) Check(ctx context.Context, expected string, domains []string) []Result {
+	var rs = []Result{}
+
+	var sorted = make([]string, len(domains))
+	copy(sorted, domains)
+	sort.Strings(sorted)
+
+	for _, d := range sorted {
+		rs = append(rs, c.CheckDomain(ctx, expected, d))
+	}
+
+	return rs
+}
+
+// CheckDomain resolves the domain and fetches its TLS certificate
+func (c *Checker) CheckDomain(ctx context.Context, expected, domain string) Result {
+	var r = Result{
+		Domain: domain,
+		DNS:    c.resolve(ctx, expected, domain),
+	}
+
+	r.Certificate, r.TLSErr = c.certificate(ctx, domain, r.DNS.Addresses)
+	return r
+}
+
+func (c *Checker) timeout() time.Duration {
+	if c.Timeout == 0 {
+		return DefaultTimeout
+	}
+
+	return c.Timeout
+}
+
+// Resolver used for looking up domains
+func (c *Checker) Resolver() *net.Resolver {
+	if c.DNSServer == "" {
+		return net.DefaultResolver
+	}
+
+	var server = c.DNSServer
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d = net.Dialer{}
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+func (c *Checker) resolve(ctx context.Context, expected, domain string) DNS {
+	var d = DNS{
+		Expected: expected,
+	}
+
+	var r = c.Resolver()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+
+	cname, err := r.LookupCNAME(ctx, domain)
+
+	if err != nil {
+		d.Err = err
+		return d
+	}
+
+	if !sameHost(cname, domain) {
+		d.CNAME = strings.TrimSuffix(cname, ".")
+	}
+
+	if d.Addresses, d.Err = lookupHost(ctx, r, domain); d.Err != nil || d.OK() {
+		return d
+	}
+
+	// apex domains can't have CNAME records, so compare the addresses instead
+	d.ExpectedAddresses, _ = lookupHost(ctx, r, expected)
+	return d
+}
+
+func lookupHost(ctx context.Context, r *net.Resolver, host string) ([]string, error) {
+	var addrs, err = r.LookupHost(ctx, host)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(addrs)
+	return addrs, nil
+}
+
+// certificate served on the resolved addresses, so it comes from the same host checked on the DNS
+func (c *Checker) certificate(ctx context.Context, domain string, addresses []string) (*Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout())
+	defer cancel()
+
+	var dial = c.Dial
+
+	if dial == nil {
+		var d = &net.Dialer{
+			Resolver: c.Resolver(),
+		}
+
+		dial = d.DialContext
+	}
+
+	var host = domain
+
+	if len(addresses) != 0 {
+		host = addresses[0]
+	}
+
+	conn, err := dial(ctx, "tcp", net.JoinHostPort(host, "443"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// verification is done afterwards to report on invalid certificates too
+	var tc = tls.Client(conn, &tls.Config{
+		ServerName:         domain,
+		InsecureSkipVerify: true, // #nosec
+	})
+
+	if err := tc.Handshake(); err != nil {
+		return nil, errwrap.Wrapf("TLS handshake failed: {{err}}", err)
+	}
+
+	var chain = tc.ConnectionState().PeerCertificates
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificate served for %s", domain)
+	}
+
+	var leaf = chain[0]
+	var intermediates = x509.NewCertPool()
+
+	for _, ic := range chain[1:] {
+		intermediates.AddCert(ic)
+	}
+
+	var cert = &Certificate{
+		Issuer:    name(leaf.Issuer.CommonName, leaf.Issuer.Organization),
+		Subject:   name(leaf.Subject.CommonName, leaf.Subject.Organization),
+		SANs:      leaf.DNSNames,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+		ExpiresIn: time.Until(leaf.NotAfter),
+	}
+
+	_, cert.VerifyErr = leaf.Verify(x509.VerifyOptions{
+		DNSName:       domain,
+		Roots:         c.RootCAs,
+		Intermediates: intermediates,
+	})
+
+	return cert, nil
+}
+
+func name(cn string, org []string) string {
+	if cn != "" {
+		return cn
+	}
+
+	return strings.Join(org, ", ")
+}
+
+func sameHost(a, b string) bool {
+	return a != "" && strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+func days(d time.Duration) string {
+	var n = int(d.Hours() / 24)
+
+	if n == 1 {
+		return "1 day"
+	}
+
+	return fmt.Sprintf("%d days", n)
+}
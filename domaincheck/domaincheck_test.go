@@ -0,0 +1,325 @@
+package domaincheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/tdata"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+var update bool
+
+func init() {
+	flag.BoolVar(&update, "update", false, "update golden files")
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
+type record struct {
+	CNAME string
+	A     []string
+}
+
+// startDNS starts a stand-in DNS server answering A queries for the given records
+func startDNS(t *testing.T, records map[string]record) string {
+	var conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("Expected no error listening, got %v instead", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		var buf = make([]byte, 1500)
+
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+
+			if err != nil {
+				return
+			}
+
+			if b := answer(buf[:n], records); b != nil {
+				_, _ = conn.WriteTo(b, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func answer(req []byte, records map[string]record) []byte {
+	var p dnsmessage.Parser
+	var h, err = p.Start(req)
+
+	if err != nil {
+		return nil
+	}
+
+	q, err := p.Question()
+
+	if err != nil {
+		return nil
+	}
+
+	var msg = dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:            h.ID,
+			Response:      true,
+			Authoritative: true,
+		},
+		Questions: []dnsmessage.Question{q},
+	}
+
+	var name = strings.ToLower(q.Name.String())
+	var r, ok = records[name]
+
+	if !ok {
+		msg.Header.RCode = dnsmessage.RCodeNameError
+		b, _ := msg.Pack()
+		return b
+	}
+
+	if r.CNAME != "" {
+		msg.Answers = append(msg.Answers, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{
+				Name:  q.Name,
+				Type:  dnsmessage.TypeCNAME,
+				Class: dnsmessage.ClassINET,
+				TTL:   60,
+			},
+			Body: &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(r.CNAME)},
+		})
+
+		name = r.CNAME
+		r = records[name]
+	}
+
+	if q.Type == dnsmessage.TypeA {
+		for _, a := range r.A {
+			var ar = dnsmessage.AResource{}
+			copy(ar.A[:], net.ParseIP(a).To4())
+
+			msg.Answers = append(msg.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{
+					Name:  dnsmessage.MustNewName(name),
+					Type:  dnsmessage.TypeA,
+					Class: dnsmessage.ClassINET,
+					TTL:   60,
+				},
+				Body: &ar,
+			})
+		}
+	}
+
+	b, _ := msg.Pack()
+	return b
+}
+
+var records = map[string]record{
+	"web-acme.lfr.cloud.":   {A: []string{"10.0.0.1"}},
+	"shop.example.net.":     {CNAME: "web-acme.lfr.cloud."},
+	"example.com.":          {A: []string{"10.0.0.1"}},
+	"wrong.example.com.":    {CNAME: "old-host.example.net."},
+	"old-host.example.net.": {A: []string{"10.9.9.9"}},
+}
+
+func newChecker(t *testing.T) *Checker {
+	var server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	var roots = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	return &Checker{
+		DNSServer: startDNS(t, records),
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d = net.Dialer{}
+			return d.DialContext(ctx, network, server.Listener.Addr().String())
+		},
+		RootCAs: roots,
+		Timeout: 5 * time.Second,
+	}
+}
+
+func TestCheckDomainCNAME(t *testing.T) {
+	var c = newChecker(t)
+	var r = c.CheckDomain(context.Background(), "web-acme.lfr.cloud", "shop.example.net")
+
+	if r.DNS.Err != nil {
+		t.Fatalf("Expected no DNS error, got %v instead", r.DNS.Err)
+	}
+
+	if r.DNS.CNAME != "web-acme.lfr.cloud" {
+		t.Errorf("Expected CNAME to be web-acme.lfr.cloud, got %v instead", r.DNS.CNAME)
+	}
+
+	if !r.DNS.OK() {
+		t.Errorf("Expected DNS to point to the service endpoint")
+	}
+
+	if r.TLSErr != nil {
+		t.Fatalf("Expected no TLS error, got %v instead", r.TLSErr)
+	}
+
+	// the test certificate is only valid for example.com and *.example.com
+	if r.Certificate.VerifyErr == nil {
+		t.Errorf("Expected certificate not to be valid for shop.example.net")
+	}
+
+	if r.OK() {
+		t.Errorf("Expected problems to be found, got none")
+	}
+}
+
+func TestCheckDomainApex(t *testing.T) {
+	var c = newChecker(t)
+	var r = c.CheckDomain(context.Background(), "web-acme.lfr.cloud", "example.com")
+
+	if !r.DNS.OK() {
+		t.Errorf("Expected DNS to point to the service endpoint, got %+v instead", r.DNS)
+	}
+
+	if r.DNS.CNAME != "" {
+		t.Errorf("Expected no CNAME, got %v instead", r.DNS.CNAME)
+	}
+
+	if r.Certificate == nil {
+		t.Fatalf("Expected certificate, got TLS error %v instead", r.TLSErr)
+	}
+
+	if r.Certificate.VerifyErr != nil {
+		t.Errorf("Expected certificate to be valid, got %v instead", r.Certificate.VerifyErr)
+	}
+
+	if !r.OK() {
+		t.Errorf("Expected no problems, got %v instead", r.Problems())
+	}
+}
+
+func TestCheckDomainDialResolvedAddress(t *testing.T) {
+	var c = newChecker(t)
+	var dial = c.Dial
+	var dialed []string
+
+	c.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		return dial(ctx, network, address)
+	}
+
+	c.CheckDomain(context.Background(), "web-acme.lfr.cloud", "shop.example.net")
+
+	if want := []string{"10.0.0.1:443"}; !reflect.DeepEqual(dialed, want) {
+		t.Errorf("Wanted to dial %v, got %v instead", want, dialed)
+	}
+}
+
+func TestCheckDomainWrongCNAME(t *testing.T) {
+	var c = newChecker(t)
+	var r = c.CheckDomain(context.Background(), "web-acme.lfr.cloud", "wrong.example.com")
+
+	if r.DNS.CNAME != "old-host.example.net" {
+		t.Errorf("Expected CNAME to be old-host.example.net, got %v instead", r.DNS.CNAME)
+	}
+
+	if r.DNS.OK() {
+		t.Errorf("Expected DNS not to point to the service endpoint")
+	}
+
+	var want = "wrong.example.com doesn't point to web-acme.lfr.cloud"
+
+	if p := r.Problems(); len(p) == 0 || p[0] != want {
+		t.Errorf("Expected first problem to be %v, got %v instead", want, p)
+	}
+}
+
+func TestCheckDomainNotFound(t *testing.T) {
+	var c = newChecker(t)
+	var r = c.CheckDomain(context.Background(), "web-acme.lfr.cloud", "missing.example.com")
+
+	if r.DNS.Err == nil {
+		t.Errorf("Expected DNS error, got nil instead")
+	}
+}
+
+func TestFprint(t *testing.T) {
+	var defaultNoColor = color.NoColor
+	color.NoColor = true
+
+	defer func() {
+		color.NoColor = defaultNoColor
+	}()
+
+	var expires = time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	var rs = []Result{
+		{
+			Domain: "www.example.com",
+			DNS: DNS{
+				Expected:  "web-acme.lfr.cloud",
+				CNAME:     "web-acme.lfr.cloud",
+				Addresses: []string{"10.0.0.1"},
+			},
+			Certificate: &Certificate{
+				Issuer:    "Let's Encrypt Authority X3",
+				SANs:      []string{"example.com", "www.example.com"},
+				NotAfter:  expires,
+				ExpiresIn: 60 * 24 * time.Hour,
+			},
+		},
+		{
+			Domain: "wrong.example.com",
+			DNS: DNS{
+				Expected:          "web-acme.lfr.cloud",
+				CNAME:             "old-host.example.net",
+				Addresses:         []string{"10.9.9.9"},
+				ExpectedAddresses: []string{"10.0.0.1"},
+			},
+			Certificate: &Certificate{
+				Issuer:    "Let's Encrypt Authority X3",
+				SANs:      []string{"wrong.example.com"},
+				NotAfter:  expires,
+				ExpiresIn: 3 * 24 * time.Hour,
+			},
+		},
+		{
+			Domain: "missing.example.com",
+			DNS: DNS{
+				Expected: "web-acme.lfr.cloud",
+				Err:      errors.New("no such host"),
+			},
+			TLSErr: errors.New("connection refused"),
+		},
+	}
+
+	var b bytes.Buffer
+
+	for _, r := range rs {
+		Fprint(&b, r)
+	}
+
+	if update {
+		tdata.ToFile("mocks/print", b.String())
+	}
+
+	if want := tdata.FromFile("mocks/print"); b.String() != want {
+		t.Errorf("Wanted %v, got %v instead", want, b.String())
+	}
+}
@@ -0,0 +1,63 @@
+package domaincheck
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/henvic/wedeploycli/color"
+)
+
+// Fprint the result of checking a domain
+func Fprint(w io.Writer, r Result) {
+	var mark = color.Format(color.FgGreen, "✔")
+
+	if !r.OK() {
+		mark = color.Format(color.FgRed, "✖")
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s\n", mark, color.Format(color.Bold, r.Domain))
+	fprintDNS(w, r.DNS)
+
+	if r.Certificate != nil {
+		fprintCertificate(w, *r.Certificate)
+	}
+
+	for _, p := range r.Problems() {
+		_, _ = fmt.Fprintf(w, "    %s %s\n", color.Format(color.FgRed, "!"), p)
+	}
+}
+
+func fprintDNS(w io.Writer, d DNS) {
+	if d.Err != nil {
+		return
+	}
+
+	if d.CNAME != "" {
+		_, _ = fmt.Fprintf(w, "    %s %s\n", label("CNAME"), d.CNAME)
+	}
+
+	_, _ = fmt.Fprintf(w, "    %s %s\n", label("Addresses"), strings.Join(d.Addresses, ", "))
+	_, _ = fmt.Fprintf(w, "    %s %s\n", label("Expected"), d.Expected)
+
+	if len(d.ExpectedAddresses) != 0 {
+		_, _ = fmt.Fprintf(w, "    %s %s\n", label("Expected addresses"), strings.Join(d.ExpectedAddresses, ", "))
+	}
+}
+
+func fprintCertificate(w io.Writer, c Certificate) {
+	_, _ = fmt.Fprintf(w, "    %s %s\n", label("Issuer"), c.Issuer)
+	_, _ = fmt.Fprintf(w, "    %s %s\n", label("SANs"), strings.Join(c.SANs, ", "))
+
+	var expires = c.NotAfter.UTC().Format("2006-01-02 15:04:05 MST")
+
+	if c.ExpiresIn > 0 {
+		expires += color.Format(color.FgHiBlack, " (in %s)", days(c.ExpiresIn))
+	}
+
+	_, _ = fmt.Fprintf(w, "    %s %s\n", label("Expires"), expires)
+}
+
+func label(s string) string {
+	return color.Format(color.FgHiBlack, s+":")
+}
@@ -0,0 +1,109 @@
+package drift
+
+import (
+	"context"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/services"
+)
+
+// fields that can be changed without deploying the service again
+var applicableFields = map[string]bool{
+	"scale":         true,
+	"customDomains": true,
+}
+
+// Applicable tells if there are changes that can be applied without a deployment
+func (s Service) Applicable() bool {
+	if s.Missing {
+		return false
+	}
+
+	for _, d := range s.Differences {
+		if applicableFields[d.Field] {
+			return true
+		}
+	}
+
+	return !s.Env.Empty()
+}
+
+// RequiresDeploy returns the fields that can only be changed by deploying the service
+func (s Service) RequiresDeploy() []string {
+	var fields []string
+
+	for _, d := range s.Differences {
+		if !applicableFields[d.Field] {
+			fields = append(fields, d.Field)
+		}
+	}
+
+	return fields
+}
+
+// Apply the local configuration of a service, excluding changes that require a deployment
+func (c *Checker) Apply(ctx context.Context, projectID string, s Service) error {
+	if !s.Applicable() {
+		return nil
+	}
+
+	for _, d := range s.Differences {
+		if d.Field != "scale" {
+			continue
+		}
+
+		if err := c.Client.Scale(ctx, projectID, s.ServiceID, services.Scale{
+			Current: s.Package.Scale,
+		}); err != nil {
+			return errwrap.Wrapf("can't scale service "+s.ServiceID+": {{err}}", err)
+		}
+	}
+
+	if err := c.applyEnv(ctx, projectID, s); err != nil {
+		return err
+	}
+
+	return c.applyDomains(ctx, projectID, s)
+}
+
+func (c *Checker) applyEnv(ctx context.Context, projectID string, s Service) error {
+	if s.Env.Empty() || s.Package.Env == nil {
+		return nil
+	}
+
+	var local = []services.EnvironmentVariable{}
+
+	for k, v := range s.Package.Env {
+		local = append(local, services.EnvironmentVariable{Name: k, Value: v})
+	}
+
+	var next = envfile.Merge(s.RemoteEnv, local)
+
+	if c.Options.StrictEnv {
+		envfile.Sort(local)
+		next = local
+	}
+
+	if err := c.Client.SetEnvironmentVariables(ctx, projectID, s.ServiceID, next); err != nil {
+		return errwrap.Wrapf("can't set environment variables of service "+s.ServiceID+": {{err}}", err)
+	}
+
+	return nil
+}
+
+func (c *Checker) applyDomains(ctx context.Context, projectID string, s Service) error {
+	for _, d := range s.AddDomains {
+		if err := c.Client.AddDomain(ctx, projectID, s.ServiceID, d); err != nil {
+			return errwrap.Wrapf("can't add domain "+d+" to service "+s.ServiceID+": {{err}}", err)
+		}
+	}
+
+	for _, d := range s.RemoveDomains {
+		if err := c.Client.RemoveDomain(ctx, projectID, s.ServiceID, d); err != nil {
+			return errwrap.Wrapf("can't remove domain "+d+" from service "+s.ServiceID+": {{err}}", err)
+		}
+	}
+
+	return nil
+}
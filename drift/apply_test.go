@@ -0,0 +1,209 @@
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+func TestApplicable(t *testing.T) {
+	var cases = []struct {
+		s    Service
+		want bool
+	}{
+		{Service{}, false},
+		{Service{Missing: true, Differences: []Difference{{Field: "scale"}}}, false},
+		{Service{Differences: []Difference{{Field: "image"}}}, false},
+		{Service{Differences: []Difference{{Field: "scale"}}}, true},
+		{Service{Env: envfile.Changes{Added: []services.EnvironmentVariable{{Name: "A"}}}}, true},
+	}
+
+	for _, c := range cases {
+		if got := c.s.Applicable(); got != c.want {
+			t.Errorf("Wanted applicable(%+v) = %v, got %v instead", c.s, c.want, got)
+		}
+	}
+
+	var s = Service{Differences: []Difference{{Field: "image"}, {Field: "scale"}, {Field: "memory"}}}
+
+	if got, want := s.RequiresDeploy(), []string{"image", "memory"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Wanted %v, got %v instead", want, got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var scale, envs, domains string
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web/scale",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPatch {
+				t.Errorf("Unexpected method %v", r.Method)
+			}
+
+			scale = readBody(t, r)
+		})
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web/environment-variables",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				t.Errorf("Unexpected method %v", r.Method)
+			}
+
+			envs = readBody(t, r)
+		})
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web",
+		tdata.ServerJSONFileHandler("mocks/service_response.json"))
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web/custom-domains",
+		func(w http.ResponseWriter, r *http.Request) {
+			domains = readBody(t, r)
+		})
+
+	var remote = services.Service{
+		ServiceID:     "web",
+		Image:         "liferaycloud/nginx:1.14",
+		Scale:         3,
+		CustomDomains: []string{"example.com"},
+	}
+
+	var remoteEnvs = []services.EnvironmentVariable{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "HOTFIX", Value: "true"},
+	}
+
+	var pkg = services.Package{
+		ID:            "web",
+		Scale:         2,
+		CustomDomains: []string{"example.com", "new.example.com"},
+		Env: map[string]string{
+			"LOG_LEVEL": "info",
+		},
+	}
+
+	var c = Checker{
+		Client: client,
+	}
+
+	if err := c.Apply(context.Background(), "acme", Compare(pkg, remote, remoteEnvs, Options{})); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if want := `{"value":2}`; scale != want {
+		t.Errorf("Wanted scale body %v, got %v instead", want, scale)
+	}
+
+	if want := `{"env":{"HOTFIX":"true","LOG_LEVEL":"info"}}`; envs != want {
+		t.Errorf("Wanted env body %v, got %v instead", want, envs)
+	}
+
+	// the mock always returns example.com and old.example.com as the current domains
+	if want := `{"value":["example.com","old.example.com","new.example.com"]}`; domains != want {
+		t.Errorf("Wanted domains body %v, got %v instead", want, domains)
+	}
+}
+
+func TestApplyStrictEnv(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var envs string
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web/environment-variables",
+		func(w http.ResponseWriter, r *http.Request) {
+			envs = readBody(t, r)
+		})
+
+	var pkg = services.Package{
+		ID: "web",
+		Env: map[string]string{
+			"LOG_LEVEL": "info",
+		},
+	}
+
+	var remoteEnvs = []services.EnvironmentVariable{
+		{Name: "LOG_LEVEL", Value: "info"},
+		{Name: "HOTFIX", Value: "true"},
+	}
+
+	var c = Checker{
+		Client: client,
+		Options: Options{
+			StrictEnv: true,
+		},
+	}
+
+	var s = Compare(pkg, services.Service{ServiceID: "web"}, remoteEnvs, c.Options)
+
+	if err := c.Apply(context.Background(), "acme", s); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if want := `{"env":{"LOG_LEVEL":"info"}}`; envs != want {
+		t.Errorf("Wanted env body %v, got %v instead", want, envs)
+	}
+}
+
+func TestApplyStrictEnvNotDefined(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web/environment-variables",
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Unexpected request to set environment variables")
+		})
+
+	var pkg = services.Package{
+		ID:    "web",
+		Scale: 1,
+	}
+
+	var remoteEnvs = []services.EnvironmentVariable{
+		{Name: "HOTFIX", Value: "true"},
+	}
+
+	var c = Checker{
+		Client: client,
+		Options: Options{
+			StrictEnv: true,
+		},
+	}
+
+	var s = Compare(pkg, services.Service{ServiceID: "web", Scale: 1}, remoteEnvs, c.Options)
+
+	if s.Drifted() {
+		t.Errorf("Expected no drift without env defined locally, got %+v instead", s)
+	}
+
+	if err := c.Apply(context.Background(), "acme", s); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+}
+
+func readBody(t *testing.T, r *http.Request) string {
+	var b, err = ioutil.ReadAll(r.Body)
+
+	if err != nil {
+		t.Errorf("Expected no error reading body, got %v instead", err)
+	}
+
+	var m interface{}
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Errorf("Expected valid JSON body, got %v instead", err)
+	}
+
+	compact, _ := json.Marshal(m)
+	return string(compact)
+}
@@ -0,0 +1,240 @@
+// Package drift detects configuration drift between LCP.json files and live services.
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/apihelper"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/services"
+)
+
+// Difference between the local and the remote value of a field
+type Difference struct {
+	Field  string
+	Local  string
+	Remote string
+}
+
+// Service drift
+type Service struct {
+	ServiceID string
+
+	// Missing is true when the service doesn't exist remotely
+	Missing bool
+
+	Differences []Difference
+
+	// Env changes required to go from the remote to the local environment variables
+	Env envfile.Changes
+
+	// RemoteEnv is the current set of environment variables of the service
+	RemoteEnv []services.EnvironmentVariable
+
+	// Domains changes required to go from the remote to the local custom domains
+	AddDomains    []string
+	RemoveDomains []string
+
+	// Package of the local service
+	Package services.Package
+}
+
+// Drifted tells if the service configuration drifted
+func (s Service) Drifted() bool {
+	return s.Missing || len(s.Differences) != 0 || !s.Env.Empty()
+}
+
+// Options for comparing services
+type Options struct {
+	// StrictEnv considers environment variables only defined remotely as drift
+	StrictEnv bool
+}
+
+// Checker for drift
+type Checker struct {
+	Client  *services.Client
+	Options Options
+}
+
+// Check services of a project for drift
+func (c *Checker) Check(ctx context.Context, projectID string, pkgs []services.Package) ([]Service, error) {
+	var list = []Service{}
+
+	for _, pkg := range pkgs {
+		var s, err = c.check(ctx, projectID, pkg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, s)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].ServiceID < list[j].ServiceID
+	})
+
+	return list, nil
+}
+
+func (c *Checker) check(ctx context.Context, projectID string, pkg services.Package) (Service, error) {
+	var remote, err = c.Client.Get(ctx, projectID, pkg.ID)
+
+	if epf, ok := err.(apihelper.APIFault); ok && epf.Status == http.StatusNotFound {
+		return Service{
+			ServiceID: pkg.ID,
+			Missing:   true,
+			Package:   pkg,
+		}, nil
+	}
+
+	if err != nil {
+		return Service{}, errwrap.Wrapf("can't get service "+pkg.ID+": {{err}}", err)
+	}
+
+	envs, err := c.Client.GetEnvironmentVariables(ctx, projectID, pkg.ID)
+
+	if err != nil {
+		return Service{}, errwrap.Wrapf("can't get environment variables of service "+pkg.ID+": {{err}}", err)
+	}
+
+	return Compare(pkg, remote, envs, c.Options), nil
+}
+
+// LocalPackages of the services found on a directory, optionally filtered by service ID
+func LocalPackages(dir, serviceID string) ([]services.Package, error) {
+	var list, err = services.GetListFromDirectory(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs = []services.Package{}
+
+	for _, s := range list {
+		if s.ServiceID == "" || (serviceID != "" && s.ServiceID != serviceID) {
+			continue
+		}
+
+		pkgs = append(pkgs, s.Package())
+	}
+
+	if len(pkgs) == 0 {
+		return nil, errors.New("no services found")
+	}
+
+	return pkgs, nil
+}
+
+// Compare a local package with a remote service and its environment variables.
+// Fields not defined locally are not compared.
+func Compare(pkg services.Package, remote services.Service, envs []services.EnvironmentVariable, o Options) Service {
+	var s = Service{
+		ServiceID: pkg.ID,
+		RemoteEnv: envs,
+		Package:   pkg,
+	}
+
+	if pkg.Image != "" && pkg.Image != remote.Image {
+		s.add("image", pkg.Image, remote.Image)
+	}
+
+	if pkg.Scale != 0 && pkg.Scale != remote.Scale {
+		s.add("scale", strconv.Itoa(pkg.Scale), strconv.Itoa(remote.Scale))
+	}
+
+	if pkg.CPU != "" && !sameNumber(pkg.CPU, remote.CPU) {
+		s.add("cpu", pkg.CPU.String(), remote.CPU.String())
+	}
+
+	if pkg.Memory != "" && !sameNumber(pkg.Memory, remote.Memory) {
+		s.add("memory", pkg.Memory.String(), remote.Memory.String())
+	}
+
+	if len(pkg.CustomDomains) != 0 {
+		s.compareDomains(pkg.CustomDomains, remote.CustomDomains)
+	}
+
+	// an empty "env" object is defined, and removes all variables on strict mode
+	if pkg.Env != nil {
+		s.Env = compareEnv(pkg.Env, envs, o.StrictEnv)
+	}
+
+	return s
+}
+
+func (s *Service) add(field, local, remote string) {
+	s.Differences = append(s.Differences, Difference{
+		Field:  field,
+		Local:  local,
+		Remote: remote,
+	})
+}
+
+func (s *Service) compareDomains(local, remote []string) {
+	s.AddDomains = subtract(local, remote)
+	s.RemoveDomains = subtract(remote, local)
+
+	if len(s.AddDomains) != 0 || len(s.RemoveDomains) != 0 {
+		s.add("customDomains", joinSorted(local), joinSorted(remote))
+	}
+}
+
+func compareEnv(local map[string]string, remote []services.EnvironmentVariable, strict bool) envfile.Changes {
+	var to = []services.EnvironmentVariable{}
+
+	for k, v := range local {
+		to = append(to, services.EnvironmentVariable{Name: k, Value: v})
+	}
+
+	var changes = envfile.Diff(remote, to)
+
+	if !strict {
+		changes.Removed = nil
+	}
+
+	return changes
+}
+
+func subtract(a, b []string) []string {
+	var m = map[string]bool{}
+	var r []string
+
+	for _, v := range b {
+		m[v] = true
+	}
+
+	for _, v := range a {
+		if !m[v] {
+			r = append(r, v)
+		}
+	}
+
+	sort.Strings(r)
+	return r
+}
+
+func joinSorted(s []string) string {
+	var c = make([]string, len(s))
+	copy(c, s)
+	sort.Strings(c)
+	return strings.Join(c, ", ")
+}
+
+func sameNumber(a, b json.Number) bool {
+	var af, errA = a.Float64()
+	var bf, errB = b.Float64()
+
+	if errA != nil || errB != nil {
+		return a == b
+	}
+
+	return af == bf
+}
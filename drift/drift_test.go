@@ -0,0 +1,267 @@
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/envmask"
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/tdata"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+var (
+	wectx  config.Context
+	client *services.Client
+	update bool
+)
+
+func init() {
+	flag.BoolVar(&update, "update", false, "update golden files")
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	var err error
+	wectx, err = config.Setup("mocks/.lcp")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := wectx.SetEndpoint(defaults.CloudRemote); err != nil {
+		panic(err)
+	}
+
+	client = services.New(wectx)
+	os.Exit(m.Run())
+}
+
+var web = services.Package{
+	ID:            "web",
+	Image:         "liferaycloud/nginx:1.14",
+	Scale:         2,
+	CPU:           json.Number("1.0"),
+	Memory:        json.Number("1024"),
+	CustomDomains: []string{"example.com", "new.example.com"},
+	Env: map[string]string{
+		"LOG_LEVEL": "info",
+		"PORT":      "80",
+	},
+}
+
+func TestCompare(t *testing.T) {
+	var remote = services.Service{
+		ServiceID:     "web",
+		Image:         "liferaycloud/nginx:1.14",
+		Scale:         3,
+		CPU:           json.Number("1"),
+		Memory:        json.Number("512"),
+		CustomDomains: []string{"old.example.com", "example.com"},
+	}
+
+	var envs = []services.EnvironmentVariable{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "PORT", Value: "80"},
+		{Name: "HOTFIX", Value: "true"},
+	}
+
+	var got = Compare(web, remote, envs, Options{})
+
+	var want = Service{
+		ServiceID: "web",
+		Differences: []Difference{
+			{Field: "scale", Local: "2", Remote: "3"},
+			{Field: "memory", Local: "1024", Remote: "512"},
+			{Field: "customDomains", Local: "example.com, new.example.com", Remote: "example.com, old.example.com"},
+		},
+		Env: envfile.Changes{
+			Changed:   []envfile.Change{{Name: "LOG_LEVEL", From: "debug", To: "info"}},
+			Unchanged: []services.EnvironmentVariable{{Name: "PORT", Value: "80"}},
+		},
+		AddDomains:    []string{"new.example.com"},
+		RemoveDomains: []string{"old.example.com"},
+		RemoteEnv:     envs,
+		Package:       web,
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Compare does not match with wanted structure.")
+		t.Errorf(pretty.Compare(want, got))
+	}
+
+	if !got.Drifted() {
+		t.Errorf("Expected service to have drifted")
+	}
+
+	var strict = Compare(web, remote, envs, Options{StrictEnv: true})
+	var wantRemoved = []services.EnvironmentVariable{{Name: "HOTFIX", Value: "true"}}
+
+	if !reflect.DeepEqual(strict.Env.Removed, wantRemoved) {
+		t.Errorf("Wanted removed env to be %+v, got %+v instead", wantRemoved, strict.Env.Removed)
+	}
+}
+
+func TestCompareNoDrift(t *testing.T) {
+	var pkg = services.Package{
+		ID:    "web",
+		Scale: 3,
+		Env: map[string]string{
+			"PORT": "80",
+		},
+	}
+
+	var remote = services.Service{
+		ServiceID: "web",
+		Image:     "liferaycloud/nginx:1.14",
+		Scale:     3,
+	}
+
+	var envs = []services.EnvironmentVariable{
+		{Name: "PORT", Value: "80"},
+		{Name: "HOTFIX", Value: "true"},
+	}
+
+	if got := Compare(pkg, remote, envs, Options{}); got.Drifted() {
+		t.Errorf("Expected no drift, got %+v instead", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web",
+		tdata.ServerJSONFileHandler("mocks/service_response.json"))
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web/environment-variables",
+		tdata.ServerJSONFileHandler("mocks/envs_response.json"))
+
+	servertest.Mux.HandleFunc("/projects/acme/services/worker",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(tdata.FromFile("mocks/not_found_response.json")))
+		})
+
+	var c = Checker{
+		Client: client,
+	}
+
+	var got, err = c.Check(context.Background(), "acme", []services.Package{
+		{ID: "worker", Scale: 1},
+		web,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 services, got %d instead", len(got))
+	}
+
+	if got[0].ServiceID != "web" || len(got[0].Differences) != 3 || len(got[0].Env.Changed) != 1 {
+		t.Errorf("Unexpected drift for web: %+v", got[0])
+	}
+
+	if got[1].ServiceID != "worker" || !got[1].Missing || !got[1].Drifted() {
+		t.Errorf("Expected worker to be missing, got %+v instead", got[1])
+	}
+}
+
+func TestCheckFailure(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	servertest.Mux.HandleFunc("/projects/acme/services/web",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+	var c = Checker{
+		Client: client,
+	}
+
+	if _, err := c.Check(context.Background(), "acme", []services.Package{web}); err == nil {
+		t.Errorf("Expected error, got nil instead")
+	}
+}
+
+func TestFprint(t *testing.T) {
+	var defaultNoColor = color.NoColor
+	color.NoColor = true
+
+	defer func() {
+		color.NoColor = defaultNoColor
+	}()
+
+	var remote = services.Service{
+		ServiceID:     "web",
+		Image:         "liferaycloud/nginx:1.14",
+		Scale:         3,
+		CPU:           json.Number("1"),
+		Memory:        json.Number("512"),
+		CustomDomains: []string{"old.example.com", "example.com"},
+	}
+
+	var envs = []services.EnvironmentVariable{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "API_TOKEN", Value: "abc"},
+		{Name: "HOTFIX", Value: "true"},
+	}
+
+	var pkg = web
+	pkg.Env = map[string]string{
+		"LOG_LEVEL": "info",
+		"API_TOKEN": "def",
+		"NEW":       "1",
+	}
+
+	var b bytes.Buffer
+
+	Fprint(&b, Compare(pkg, remote, envs, Options{StrictEnv: true}), envmask.New("", false))
+	Fprint(&b, Service{ServiceID: "worker", Missing: true}, envmask.Masker{})
+	Fprint(&b, Service{ServiceID: "db"}, envmask.Masker{})
+
+	if update {
+		tdata.ToFile("mocks/print", b.String())
+	}
+
+	if want := tdata.FromFile("mocks/print"); b.String() != want {
+		t.Errorf("Wanted %v, got %v instead", want, b.String())
+	}
+}
+
+func TestLocalPackages(t *testing.T) {
+	var pkgs, err = LocalPackages("mocks/project", "")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if len(pkgs) != 2 || pkgs[0].ID != "web" || pkgs[0].Memory != "1024" || pkgs[1].ID != "worker" {
+		t.Errorf("Unexpected packages: %+v", pkgs)
+	}
+
+	pkgs, err = LocalPackages("mocks/project", "worker")
+
+	if err != nil || len(pkgs) != 1 || pkgs[0].ID != "worker" {
+		t.Errorf("Expected only worker package, got %+v (error: %v) instead", pkgs, err)
+	}
+
+	if _, err = LocalPackages("mocks/project", "other"); err == nil || err.Error() != "no services found" {
+		t.Errorf("Expected no services found error, got %v instead", err)
+	}
+}
@@ -0,0 +1,57 @@
+package drift
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/envmask"
+)
+
+// Fprint the drift of a service, from the remote to the local configuration
+func Fprint(w io.Writer, s Service, m envmask.Masker) {
+	if !s.Drifted() {
+		_, _ = fmt.Fprintf(w, "%s %s\n", color.Format(color.FgHiBlack, "="), s.ServiceID)
+		return
+	}
+
+	if s.Missing {
+		_, _ = fmt.Fprintf(w, "%s %s %s\n",
+			color.Format(color.FgGreen, "+"),
+			color.Format(color.Bold, s.ServiceID),
+			color.Format(color.FgHiBlack, "(not found remotely)"))
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s\n", color.Format(color.FgYellow, "~"), color.Format(color.Bold, s.ServiceID))
+
+	for _, d := range s.Differences {
+		_, _ = fmt.Fprintf(w, "    %s: %s %s %s\n",
+			d.Field,
+			valueOrNone(d.Remote),
+			color.Format(color.FgHiBlack, "=>"),
+			valueOrNone(d.Local))
+	}
+
+	for _, e := range s.Env.Added {
+		_, _ = fmt.Fprintf(w, "    %s env %s=%s\n", color.Format(color.FgGreen, "+"), e.Name, m.Mask(e.Name, e.Value))
+	}
+
+	for _, e := range s.Env.Changed {
+		_, _ = fmt.Fprintf(w, "    %s env %s=%s %s %s\n",
+			color.Format(color.FgYellow, "~"), e.Name, m.Mask(e.Name, e.From),
+			color.Format(color.FgHiBlack, "=>"), m.Mask(e.Name, e.To))
+	}
+
+	for _, e := range s.Env.Removed {
+		_, _ = fmt.Fprintf(w, "    %s env %s\n", color.Format(color.FgRed, "-"), e.Name)
+	}
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return color.Format(color.FgHiBlack, "(none)")
+	}
+
+	return s
+}
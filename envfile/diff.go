@@ -0,0 +1,78 @@
+package envfile
+
+import (
+	"sort"
+
+	"github.com/henvic/wedeploycli/services"
+)
+
+// Change of an environment variable value
+type Change struct {
+	Name string
+	From string
+	To   string
+}
+
+// Changes between two sets of environment variables
+type Changes struct {
+	Added     []services.EnvironmentVariable
+	Changed   []Change
+	Removed   []services.EnvironmentVariable
+	Unchanged []services.EnvironmentVariable
+}
+
+// Empty returns true if there are no added, changed, or removed variables
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// Diff returns the changes required to go from a set of environment variables to another
+func Diff(from, to []services.EnvironmentVariable) Changes {
+	var c = Changes{}
+	var fm = toMap(from)
+	var tm = toMap(to)
+
+	for _, k := range sortedKeys(tm) {
+		var v = tm[k]
+		var old, ok = fm[k]
+
+		switch {
+		case !ok:
+			c.Added = append(c.Added, services.EnvironmentVariable{Name: k, Value: v})
+		case old != v:
+			c.Changed = append(c.Changed, Change{Name: k, From: old, To: v})
+		default:
+			c.Unchanged = append(c.Unchanged, services.EnvironmentVariable{Name: k, Value: v})
+		}
+	}
+
+	for _, k := range sortedKeys(fm) {
+		if _, ok := tm[k]; !ok {
+			c.Removed = append(c.Removed, services.EnvironmentVariable{Name: k, Value: fm[k]})
+		}
+	}
+
+	return c
+}
+
+// Merge environment variables, with values from next taking precedence
+func Merge(current, next []services.EnvironmentVariable) []services.EnvironmentVariable {
+	var m = toMap(current)
+
+	for _, e := range next {
+		m[e.Name] = e.Value
+	}
+
+	return fromMap(m)
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys = make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
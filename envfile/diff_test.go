@@ -0,0 +1,65 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/henvic/wedeploycli/services"
+)
+
+func TestDiff(t *testing.T) {
+	var from = []services.EnvironmentVariable{
+		{Name: "KEEP", Value: "1"},
+		{Name: "CHANGE", Value: "old"},
+		{Name: "REMOVE", Value: "x"},
+	}
+
+	var to = []services.EnvironmentVariable{
+		{Name: "ADD", Value: "new"},
+		{Name: "CHANGE", Value: "new"},
+		{Name: "KEEP", Value: "1"},
+	}
+
+	var got = Diff(from, to)
+
+	var want = Changes{
+		Added:     []services.EnvironmentVariable{{Name: "ADD", Value: "new"}},
+		Changed:   []Change{{Name: "CHANGE", From: "old", To: "new"}},
+		Removed:   []services.EnvironmentVariable{{Name: "REMOVE", Value: "x"}},
+		Unchanged: []services.EnvironmentVariable{{Name: "KEEP", Value: "1"}},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+
+	if got.Empty() {
+		t.Errorf("Expected changes not to be empty")
+	}
+
+	if !Diff(from, from).Empty() {
+		t.Errorf("Expected no changes when comparing the same set")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var current = []services.EnvironmentVariable{
+		{Name: "B", Value: "2"},
+		{Name: "A", Value: "1"},
+	}
+
+	var next = []services.EnvironmentVariable{
+		{Name: "B", Value: "3"},
+		{Name: "C", Value: "4"},
+	}
+
+	var want = []services.EnvironmentVariable{
+		{Name: "A", Value: "1"},
+		{Name: "B", Value: "3"},
+		{Name: "C", Value: "4"},
+	}
+
+	if got := Merge(current, next); !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+}
@@ -0,0 +1,399 @@
+package envfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/services"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Format of environment variables files
+type Format string
+
+const (
+	// Dotenv format (KEY=value lines)
+	Dotenv Format = "dotenv"
+
+	// JSON format (object with string values)
+	JSON Format = "json"
+
+	// YAML format (map with string values)
+	YAML Format = "yaml"
+)
+
+// ParseFormat from a string
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Dotenv, JSON, YAML:
+		return f, nil
+	case "env":
+		return Dotenv, nil
+	case "yml":
+		return YAML, nil
+	}
+
+	return "", fmt.Errorf(`unsupported format "%s": use dotenv, json, or yaml`, s)
+}
+
+// FormatFromFilename guesses the format from the file extension, defaulting to dotenv
+func FormatFromFilename(name string) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return JSON
+	case ".yaml", ".yml":
+		return YAML
+	}
+
+	return Dotenv
+}
+
+// Decode environment variables in a given format
+func Decode(r io.Reader, f Format) ([]services.EnvironmentVariable, error) {
+	switch f {
+	case JSON:
+		return decodeJSON(r)
+	case YAML:
+		return decodeYAML(r)
+	case Dotenv:
+		return decodeDotenv(r)
+	}
+
+	return nil, fmt.Errorf(`unsupported format "%s"`, f)
+}
+
+// ReadFile decodes environment variables from a file,
+// guessing the format from the file extension if format is empty
+func ReadFile(path, format string) ([]services.EnvironmentVariable, error) {
+	var f = FormatFromFilename(path)
+
+	if format != "" {
+		var err error
+
+		if f, err = ParseFormat(format); err != nil {
+			return nil, err
+		}
+	}
+
+	var r, err = os.Open(path) // #nosec
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	envs, err := Decode(r, f)
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't read "+path+": {{err}}", err)
+	}
+
+	return envs, nil
+}
+
+func decodeJSON(r io.Reader) ([]services.EnvironmentVariable, error) {
+	var m map[string]string
+
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, errwrap.Wrapf("can't decode JSON environment variables: {{err}}", err)
+	}
+
+	return fromMap(m), nil
+}
+
+func decodeYAML(r io.Reader) ([]services.EnvironmentVariable, error) {
+	var m map[string]string
+
+	if err := yaml.NewDecoder(r).Decode(&m); err != nil && err != io.EOF {
+		return nil, errwrap.Wrapf("can't decode YAML environment variables: {{err}}", err)
+	}
+
+	return fromMap(m), nil
+}
+
+func fromMap(m map[string]string) []services.EnvironmentVariable {
+	var envs = []services.EnvironmentVariable{}
+
+	for k, v := range m {
+		envs = append(envs, services.EnvironmentVariable{
+			Name:  k,
+			Value: v,
+		})
+	}
+
+	Sort(envs)
+	return envs
+}
+
+func toMap(envs []services.EnvironmentVariable) map[string]string {
+	var m = map[string]string{}
+
+	for _, e := range envs {
+		m[e.Name] = e.Value
+	}
+
+	return m
+}
+
+// Sort environment variables by name
+func Sort(envs []services.EnvironmentVariable) {
+	sort.SliceStable(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+}
+
+var keyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
+// ErrorSyntax is used when a dotenv file can't be parsed
+type ErrorSyntax struct {
+	Line    int
+	Message string
+}
+
+func (e ErrorSyntax) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+type dotenvParser struct {
+	lines []string
+	pos   int
+	envs  []services.EnvironmentVariable
+	index map[string]int
+}
+
+func decodeDotenv(r io.Reader) ([]services.EnvironmentVariable, error) {
+	var p = &dotenvParser{
+		index: map[string]int{},
+	}
+
+	var scanner = bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		p.lines = append(p.lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errwrap.Wrapf("can't read environment variables: {{err}}", err)
+	}
+
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	return p.envs, nil
+}
+
+func (p *dotenvParser) parse() error {
+	for ; p.pos < len(p.lines); p.pos++ {
+		var line = strings.TrimSpace(p.lines[p.pos])
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := p.parseLine(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *dotenvParser) parseLine(line string) error {
+	var start = p.pos + 1
+	line = strings.TrimPrefix(line, "export ")
+
+	var kv = strings.SplitN(line, "=", 2)
+
+	if len(kv) != 2 {
+		return ErrorSyntax{start, "missing '=' on key/value pair"}
+	}
+
+	var key = strings.TrimSpace(kv[0])
+
+	if !keyRegexp.MatchString(key) {
+		return ErrorSyntax{start, fmt.Sprintf(`invalid key "%s"`, key)}
+	}
+
+	var value, err = p.parseValue(strings.TrimLeft(kv[1], " \t"))
+
+	if err != nil {
+		return ErrorSyntax{start, err.Error()}
+	}
+
+	if i, ok := p.index[key]; ok {
+		p.envs[i].Value = value
+		return nil
+	}
+
+	p.index[key] = len(p.envs)
+	p.envs = append(p.envs, services.EnvironmentVariable{
+		Name:  key,
+		Value: value,
+	})
+
+	return nil
+}
+
+func (p *dotenvParser) parseValue(v string) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+
+	switch v[0] {
+	case '"':
+		return p.parseQuoted(v[1:], '"')
+	case '\'':
+		return p.parseQuoted(v[1:], '\'')
+	}
+
+	// unquoted values end on an inline comment
+	if i := strings.Index(v, " #"); i != -1 {
+		v = v[:i]
+	}
+
+	return strings.TrimSpace(v), nil
+}
+
+// parseQuoted value, which might span multiple lines.
+// Escape sequences are only interpreted on double-quoted values.
+func (p *dotenvParser) parseQuoted(v string, quote byte) (string, error) {
+	var b strings.Builder
+
+	for {
+		for i := 0; i < len(v); i++ {
+			var c = v[i]
+
+			switch {
+			case c == quote:
+				return b.String(), checkTrailing(v[i+1:])
+			case c == '\\' && quote == '"' && i+1 < len(v):
+				i++
+				b.WriteString(unescape(v[i]))
+			default:
+				b.WriteByte(c)
+			}
+		}
+
+		if p.pos+1 >= len(p.lines) {
+			return "", errors.New("unterminated quoted value")
+		}
+
+		p.pos++
+		b.WriteByte('\n')
+		v = p.lines[p.pos]
+	}
+}
+
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case '"', '\\', '$':
+		return string(c)
+	}
+
+	return `\` + string(c)
+}
+
+func checkTrailing(s string) error {
+	s = strings.TrimSpace(s)
+
+	if s == "" || strings.HasPrefix(s, "#") {
+		return nil
+	}
+
+	return fmt.Errorf(`unexpected content after quoted value: "%s"`, s)
+}
+
+// Encode environment variables in a given format
+func Encode(w io.Writer, envs []services.EnvironmentVariable, f Format) error {
+	var sorted = make([]services.EnvironmentVariable, len(envs))
+	copy(sorted, envs)
+	Sort(sorted)
+
+	switch f {
+	case JSON:
+		return encodeJSON(w, sorted)
+	case YAML:
+		return encodeYAML(w, sorted)
+	case Dotenv:
+		return encodeDotenv(w, sorted)
+	}
+
+	return fmt.Errorf(`unsupported format "%s"`, f)
+}
+
+func encodeJSON(w io.Writer, envs []services.EnvironmentVariable) error {
+	var b, err = json.MarshalIndent(toMap(envs), "", "    ")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+func encodeYAML(w io.Writer, envs []services.EnvironmentVariable) error {
+	var ms = yaml.MapSlice{}
+
+	for _, e := range envs {
+		ms = append(ms, yaml.MapItem{Key: e.Name, Value: e.Value})
+	}
+
+	var b, err = yaml.Marshal(ms)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+func encodeDotenv(w io.Writer, envs []services.EnvironmentVariable) error {
+	var b bytes.Buffer
+
+	for _, e := range envs {
+		_, _ = fmt.Fprintf(&b, "%s=%s\n", e.Name, quote(e.Value))
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+var dotenvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"$", `\$`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+func quote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\r\n\"'\\#$=") {
+		return v
+	}
+
+	return `"` + dotenvEscaper.Replace(v) + `"`
+}
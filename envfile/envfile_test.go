@@ -0,0 +1,192 @@
+package envfile
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/henvic/wedeploycli/services"
+)
+
+func decodeFile(t *testing.T, path string) []services.EnvironmentVariable {
+	var f, err = os.Open(path)
+
+	if err != nil {
+		t.Fatalf("Cannot open file: %v", err)
+	}
+
+	defer f.Close()
+
+	envs, err := Decode(f, Dotenv)
+
+	if err != nil {
+		t.Fatalf("Expected no error decoding %v, got %v instead", path, err)
+	}
+
+	return envs
+}
+
+func TestDecodeDotenv(t *testing.T) {
+	var got = decodeFile(t, "mocks/app.env")
+
+	var want = []services.EnvironmentVariable{
+		{Name: "DB_HOST", Value: "db.internal"},
+		{Name: "DB_PORT", Value: "5433"},
+		{Name: "DB_PASSWORD", Value: `p@ss w#rd "quoted"`},
+		{Name: "GREETING", Value: `Hello $USER\n`},
+		{Name: "EMPTY", Value: ""},
+		{Name: "INLINE", Value: "value"},
+		{Name: "CERTIFICATE", Value: "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUBn\n-----END CERTIFICATE-----"},
+		{Name: "SINGLE", Value: "line one\nline two"},
+		{Name: "ESCAPES", Value: "tab\there\nnewline"},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	var got, err = ReadFile("mocks/app.env", "")
+
+	if err != nil {
+		t.Fatalf("Expected no error reading file, got %v instead", err)
+	}
+
+	if want := decodeFile(t, "mocks/app.env"); !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+
+	if _, err := ReadFile("mocks/app.env", "json"); err == nil ||
+		!strings.HasPrefix(err.Error(), "can't read mocks/app.env: ") {
+		t.Errorf("Expected decoding error, got %v instead", err)
+	}
+
+	if _, err := ReadFile("mocks/app.env", "xml"); err == nil {
+		t.Errorf("Expected unsupported format error, got nil instead")
+	}
+
+	if _, err := ReadFile("mocks/not-found.env", ""); !os.IsNotExist(err) {
+		t.Errorf("Expected file not found error, got %v instead", err)
+	}
+}
+
+func TestDecodeDotenvCRLF(t *testing.T) {
+	var got = decodeFile(t, "mocks/crlf.env")
+
+	var want = []services.EnvironmentVariable{
+		{Name: "WINDOWS", Value: "crlf"},
+		{Name: "OTHER", Value: "x"},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+}
+
+func TestDecodeDotenvErrors(t *testing.T) {
+	var cases = map[string]string{
+		"A=1\nB":            "line 2: missing '=' on key/value pair",
+		"1A=x":              `line 1: invalid key "1A"`,
+		"A=\"open\nB=2":     "line 1: unterminated quoted value",
+		"A=\"closed\" tail": `line 1: unexpected content after quoted value: "tail"`,
+	}
+
+	for content, want := range cases {
+		var _, err = Decode(strings.NewReader(content), Dotenv)
+
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected error %v for %q, got %v instead", want, content, err)
+		}
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	var envs = []services.EnvironmentVariable{
+		{Name: "PLAIN", Value: "value"},
+		{Name: "MULTI", Value: "line one\nline two"},
+		{Name: "SPECIAL", Value: `"quoted" $HOME \ # 'single'`},
+		{Name: "EMPTY", Value: ""},
+	}
+
+	for _, f := range []Format{Dotenv, JSON, YAML} {
+		var b bytes.Buffer
+
+		if err := Encode(&b, envs, f); err != nil {
+			t.Errorf("Expected no error encoding %v, got %v instead", f, err)
+		}
+
+		var got, err = Decode(&b, f)
+
+		if err != nil {
+			t.Errorf("Expected no error decoding %v, got %v instead", f, err)
+		}
+
+		Sort(got)
+
+		var want = make([]services.EnvironmentVariable, len(envs))
+		copy(want, envs)
+		Sort(want)
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("Wanted %v round trip to return %+v, got %+v instead", f, want, got)
+		}
+	}
+}
+
+func TestEncodeDotenv(t *testing.T) {
+	var envs = []services.EnvironmentVariable{
+		{Name: "B", Value: "two words"},
+		{Name: "A", Value: "1"},
+	}
+
+	var b bytes.Buffer
+
+	if err := Encode(&b, envs, Dotenv); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	var want = "A=1\nB=\"two words\"\n"
+
+	if b.String() != want {
+		t.Errorf("Wanted %q, got %q instead", want, b.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	var cases = map[string]Format{
+		"dotenv": Dotenv,
+		"env":    Dotenv,
+		"JSON":   JSON,
+		"yml":    YAML,
+		"yaml":   YAML,
+	}
+
+	for s, want := range cases {
+		if got, err := ParseFormat(s); got != want || err != nil {
+			t.Errorf("Wanted %v to be parsed as %v, got %v (error: %v) instead", s, want, got, err)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("Expected error for unsupported format, got nil instead")
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	var cases = map[string]Format{
+		"prd.env":    Dotenv,
+		".env":       Dotenv,
+		"prd.json":   JSON,
+		"prd.yaml":   YAML,
+		"config.YML": YAML,
+	}
+
+	for name, want := range cases {
+		if got := FormatFromFilename(name); got != want {
+			t.Errorf("Wanted format for %v to be %v, got %v instead", name, want, got)
+		}
+	}
+}
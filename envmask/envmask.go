@@ -0,0 +1,73 @@
+package envmask
+
+import (
+	"path"
+	"strings"
+)
+
+// Placeholder shown instead of masked values
+const Placeholder = "********"
+
+// DefaultPatterns of environment variable names holding secret values
+var DefaultPatterns = []string{"*PASSWORD*", "*SECRET*", "*TOKEN*", "*KEY*"}
+
+// Masker for hiding environment variable values
+type Masker struct {
+	// Patterns of environment variable names to mask (case-insensitive)
+	Patterns []string
+
+	// All values are masked
+	All bool
+
+	// Reveal values (disables masking)
+	Reveal bool
+}
+
+// New masker from a comma-separated list of patterns, falling back to DefaultPatterns
+func New(patterns string, all bool) Masker {
+	var m = Masker{
+		All: all,
+	}
+
+	for _, p := range strings.Split(patterns, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			m.Patterns = append(m.Patterns, p)
+		}
+	}
+
+	if len(m.Patterns) == 0 {
+		m.Patterns = DefaultPatterns
+	}
+
+	return m
+}
+
+// Masked tells whether the value of a given environment variable should be masked
+func (m Masker) Masked(name string) bool {
+	if m.Reveal {
+		return false
+	}
+
+	if m.All {
+		return true
+	}
+
+	name = strings.ToUpper(name)
+
+	for _, p := range m.Patterns {
+		if ok, _ := path.Match(strings.ToUpper(p), name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Mask the value of a given environment variable, if needed
+func (m Masker) Mask(name, value string) string {
+	if !m.Masked(name) || value == "" {
+		return value
+	}
+
+	return Placeholder
+}
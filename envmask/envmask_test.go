@@ -0,0 +1,62 @@
+package envmask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	var cases = []struct {
+		masker Masker
+		value  string
+		want   string
+	}{
+		{Masker{}, "secret", "secret"},
+		{Masker{All: true}, "secret", Placeholder},
+		{Masker{All: true}, "", ""},
+		{Masker{All: true, Reveal: true}, "secret", "secret"},
+		{Masker{Patterns: DefaultPatterns}, "secret", Placeholder},
+		{Masker{Patterns: DefaultPatterns, Reveal: true}, "secret", "secret"},
+		{Masker{Patterns: []string{"DB_*"}}, "secret", "secret"},
+	}
+
+	for _, c := range cases {
+		if got := c.masker.Mask("PASSWORD", c.value); got != c.want {
+			t.Errorf("Wanted %+v to mask %q as %q, got %q instead", c.masker, c.value, c.want, got)
+		}
+	}
+}
+
+func TestMasked(t *testing.T) {
+	var m = Masker{Patterns: DefaultPatterns}
+
+	var cases = map[string]bool{
+		"DB_PASSWORD":       true,
+		"db_password":       true,
+		"AWS_SECRET_ACCESS": true,
+		"GITHUB_TOKEN":      true,
+		"API_KEY":           true,
+		"KEYCLOAK_URL":      true,
+		"DB_HOST":           false,
+		"PORT":              false,
+	}
+
+	for name, want := range cases {
+		if got := m.Masked(name); got != want {
+			t.Errorf("Wanted masked(%v) = %v, got %v instead", name, want, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if m := New("", false); !reflect.DeepEqual(m.Patterns, DefaultPatterns) {
+		t.Errorf("Expected default patterns, got %v instead", m.Patterns)
+	}
+
+	var m = New(" DB_* , *_PASS ,", true)
+	var want = []string{"DB_*", "*_PASS"}
+
+	if !reflect.DeepEqual(m.Patterns, want) || !m.All {
+		t.Errorf("Wanted patterns %v with all values masked, got %+v instead", want, m)
+	}
+}
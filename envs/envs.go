@@ -22,4 +22,16 @@ const (
 
 	// SkipTLSVerification is used to skip the TLS/SSL verification
 	SkipTLSVerification = "WEDEPLOY_SKIP_TLS_VERIFICATION"
+
+	// SecretsPassphrase is used to decrypt and encrypt the secrets file without prompting
+	SecretsPassphrase = "WEDEPLOY_SECRETS_PASSPHRASE"
+
+	// CredentialsStore overrides the credentials_store configuration (keyring, file, or plaintext)
+	CredentialsStore = "WEDEPLOY_CREDENTIALS_STORE"
+
+	// CredentialsPassphrase is used to encrypt the credentials file instead of a machine specific key
+	CredentialsPassphrase = "WEDEPLOY_CREDENTIALS_PASSPHRASE"
+
+	// SessionExpiryWarning overrides the session_expiry_warning configuration (i.e., 24h)
+	SessionExpiryWarning = "WEDEPLOY_SESSION_EXPIRY_WARNING"
 )
@@ -55,6 +55,8 @@ func TestPrintServiceSpec(t *testing.T) {
 		`ProjectID string`,
 		`Scale int`,
 		`Image string`,
+		`CPU json.Number`,
+		`Memory json.Number`,
 		`CustomDomains []string`,
 		`Env map[string]string`,
 		`Dependencies []string`,
@@ -300,6 +302,10 @@ func TestInspectConfig(t *testing.T) {
     "EnableAnalytics": false,
     "AnalyticsID": "",
     "EnableCURL": false,
+    "MaskEnvPatterns": "",
+    "MaskAllEnvs": false,
+    "CredentialsStore": "",
+    "SessionExpiry": "",
     "Remotes": null
 }`
 
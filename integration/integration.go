@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/credentials"
 	"github.com/henvic/wedeploycli/defaults"
 	"github.com/henvic/wedeploycli/envs"
 	"github.com/henvic/wedeploycli/remotes"
@@ -234,6 +235,12 @@ func removeLoginHomeMock() {
 	if errMetrics != nil && !os.IsNotExist(errMetrics) {
 		panic(errMetrics)
 	}
+
+	var errCredentials = os.Remove(filepath.Join(weHomePath, credentials.Filename))
+
+	if errCredentials != nil && !os.IsNotExist(errCredentials) {
+		panic(errCredentials)
+	}
 }
 
 func setupLoginHome() {
@@ -244,7 +251,8 @@ func setupLoginHome() {
 		Path: file,
 
 		Params: config.Params{
-			NoColor: true,
+			NoColor:          true,
+			CredentialsStore: credentials.PlaintextStore,
 		},
 	}
 
@@ -121,6 +121,82 @@ func TestLogs(t *testing.T) {
 	e.Assert(t, cmd)
 }
 
+func TestLogsMultipleHosts(t *testing.T) {
+	defer Teardown()
+	Setup()
+
+	var requested = map[string]bool{}
+
+	servertest.IntegrationMux.HandleFunc("/projects/foo/logs",
+		func(w http.ResponseWriter, r *http.Request) {
+			requested["foo"] = true
+
+			// more than one service of the project: filtered locally
+			if r.URL.Query().Get("serviceId") != "" {
+				t.Errorf("Expected no serviceId, got %v instead", r.URL.Query().Get("serviceId"))
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			_, _ = fmt.Fprint(w, tdata.FromFile("mocks/logs/logs_response.json"))
+		})
+
+	servertest.IntegrationMux.HandleFunc("/projects/bar/logs",
+		func(w http.ResponseWriter, r *http.Request) {
+			requested["bar"] = true
+
+			if r.URL.Query().Get("serviceId") != "" {
+				t.Errorf("Expected no serviceId, got %v instead", r.URL.Query().Get("serviceId"))
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			_, _ = fmt.Fprint(w, "[]")
+		})
+
+	var cmd = &Command{
+		Args: []string{
+			"log",
+			"-u",
+			"nodejs5143-foo.wedeploy.me,other-foo.wedeploy.me,bar.wedeploy.me",
+			"--watch=false",
+			"--no-color"},
+		Env: []string{"WEDEPLOY_CUSTOM_HOME=" + GetLoginHome()},
+		Dir: "mocks/home/",
+	}
+
+	cmd.Run()
+
+	if cmd.ExitCode != 0 {
+		t.Errorf("Expected exit code to be 0, got %v instead (stderr: %v)", cmd.ExitCode, cmd.Stderr)
+	}
+
+	if !requested["foo"] || !requested["bar"] {
+		t.Errorf("Expected logs of projects foo and bar to be requested, got %v instead", requested)
+	}
+
+	var want = "foo [nodejs5143-foo_nodejs51] [2016-03-29 18:55:51,234] INFO  [main] " +
+		"com.liferay.wedeploy.server.AppServer#start:125 - Server started"
+
+	if !strings.Contains(cmd.Stdout.String(), want) {
+		t.Errorf("Wanted stdout to have %v, got %v instead", want, cmd.Stdout)
+	}
+}
+
+func TestLogsMultipleHostsWithInstance(t *testing.T) {
+	var cmd = &Command{
+		Args: []string{"log", "-u", "nodejs5143-foo.wedeploy.me,bar.wedeploy.me", "--instance", "abc"},
+		Env:  []string{"WEDEPLOY_CUSTOM_HOME=" + GetLoginHome()},
+		Dir:  "mocks/home/",
+	}
+
+	cmd.Run()
+
+	var wantErr = "--instance requires a single --url host"
+
+	if !strings.Contains(cmd.Stderr.String(), wantErr) || cmd.ExitCode == 0 {
+		t.Errorf("Wanted stderr to have %v, got %v (exit code: %v) instead", wantErr, cmd.Stderr, cmd.ExitCode)
+	}
+}
+
 func TestLogsFromCurrentWorkingOnProjectDirectoryContext(t *testing.T) {
 	defer Teardown()
 	Setup()
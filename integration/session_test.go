@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/credentials"
+	"github.com/henvic/wedeploycli/remotes"
+	"github.com/henvic/wedeploycli/servertest"
+)
+
+func setupSessionHome(t *testing.T, expires time.Time) string {
+	var home, err = ioutil.TempDir("", "lcp-session")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(home)
+	})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "admin",
+		"exp": expires.Unix(),
+	}).SignedString([]byte("secret"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mock = &config.Config{
+		Path: filepath.Join(home, ".lcp"),
+
+		Params: config.Params{
+			NoColor:          true,
+			CredentialsStore: credentials.PlaintextStore,
+		},
+	}
+
+	if err := mock.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var params = mock.GetParams()
+
+	params.Remotes.Set("local", remotes.Entry{
+		Infrastructure: fmt.Sprintf("http://localhost:%d", getIntegrationServerPort()),
+		Service:        "wedeploy.me",
+		Username:       "admin",
+		Token:          token,
+	})
+
+	if err := mock.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	return home
+}
+
+func TestExpiredSession(t *testing.T) {
+	defer Teardown()
+	Setup()
+
+	servertest.IntegrationMux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request to be made with an expired token")
+	})
+
+	var cmd = &Command{
+		Args: []string{"list", "--remote", "local", "--no-color"},
+		Env:  []string{"WEDEPLOY_CUSTOM_HOME=" + setupSessionHome(t, time.Now().Add(-2*time.Hour))},
+	}
+
+	cmd.Run()
+
+	var stderr = cmd.Stderr.String()
+
+	if cmd.ExitCode != 1 ||
+		!strings.Contains(stderr, "expired 2h 0m ago") ||
+		!strings.Contains(stderr, `run "lcp login --remote local" to log in again`) {
+		t.Errorf("Expected session expired error, got %v (exit code %d) instead", stderr, cmd.ExitCode)
+	}
+}
+
+func TestExpiringSessionWarning(t *testing.T) {
+	defer Teardown()
+	Setup()
+
+	servertest.IntegrationMux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		_, _ = fmt.Fprintf(w, "[]")
+	})
+
+	var cmd = &Command{
+		Args: []string{"list", "--remote", "local", "--no-color"},
+		Env: []string{"WEDEPLOY_CUSTOM_HOME=" +
+			setupSessionHome(t, time.Now().Add(3*time.Hour+30*time.Second))},
+	}
+
+	cmd.Run()
+
+	var stderr = cmd.Stderr.String()
+
+	if cmd.ExitCode != 0 ||
+		!strings.Contains(stderr, "Warning: your session on") ||
+		!strings.Contains(stderr, `expires in 3h 0m. Run "lcp login --remote local" to renew it.`) {
+		t.Errorf("Expected session expiring warning, got %v (exit code %d) instead", stderr, cmd.ExitCode)
+	}
+}
+
+func TestExpiringSessionWarningConfigured(t *testing.T) {
+	defer Teardown()
+	Setup()
+
+	servertest.IntegrationMux.HandleFunc("/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		_, _ = fmt.Fprintf(w, "[]")
+	})
+
+	var cmd = &Command{
+		Args: []string{"list", "--remote", "local", "--no-color"},
+		Env: []string{
+			"WEDEPLOY_CUSTOM_HOME=" + setupSessionHome(t, time.Now().Add(3*time.Hour+30*time.Second)),
+			"WEDEPLOY_SESSION_EXPIRY_WARNING=1h",
+		},
+	}
+
+	cmd.Run()
+
+	var stderr = cmd.Stderr.String()
+
+	if cmd.ExitCode != 0 || strings.Contains(stderr, "Warning: your session on") {
+		t.Errorf("Expected no session expiring warning, got %v (exit code %d) instead", stderr, cmd.ExitCode)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net/url"
 	"os"
 	"runtime"
 	"strings"
@@ -59,6 +58,7 @@ func validatePassword(password string) (bool, error) {
 // Authentication service
 type Authentication struct {
 	NoLaunchBrowser bool
+	Device          bool
 	Domains         status.Domains
 	TipCommands     bool
 	wectx           config.Context
@@ -212,6 +212,10 @@ func (a *Authentication) Run(ctx context.Context, c config.Context) error {
 		return stdinErr
 	}
 
+	if a.Device {
+		return a.deviceWorkflowAuth(ctx)
+	}
+
 	if a.NoLaunchBrowser {
 		return a.basicAuthLogin(ctx)
 	}
@@ -256,23 +260,19 @@ func (a *Authentication) browserWorkflowAuth() error {
 	defer a.wlm.Stop()
 	var service = &loginserver.Service{
 		Infrastructure: a.Domains.Infrastructure,
+		Context:        a.wectx,
 	}
-	var host, err = service.Listen(context.Background())
 
-	if err != nil {
+	if _, err := service.Listen(context.Background()); err != nil {
 		a.msg.StopText(fancy.Error("Authentication failed [1/2]"))
 		return err
 	}
 
-	var loginURL = fmt.Sprintf("%s%s%s%s",
-		defaults.DashboardURLPrefix,
-		a.wectx.InfrastructureDomain(),
-		"/login?redirect_uri=",
-		url.QueryEscape(host))
+	var loginURL = service.LoginURL(defaults.DashboardURLPrefix + a.wectx.InfrastructureDomain() + "/login")
 
 	a.maybeOpenBrowser(loginURL)
 
-	if err = service.Serve(); err != nil {
+	if err := service.Serve(); err != nil {
 		a.msg.StopText(fancy.Error("Authentication failed [1/2]"))
 		return err
 	}
@@ -288,6 +288,52 @@ func (a *Authentication) browserWorkflowAuth() error {
 	return a.saveUser(username, token)
 }
 
+func (a *Authentication) deviceWorkflowAuth(ctx context.Context) error {
+	var df = &loginserver.DeviceFlow{
+		Context: a.wectx,
+	}
+
+	var da, err = df.Authorize(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var verificationURI = da.VerificationURI
+
+	if da.VerificationURIComplete != "" {
+		verificationURI = da.VerificationURIComplete
+	}
+
+	fmt.Println(fancy.Info("Open this URL on another device and confirm the code to log in:"))
+	fmt.Println(color.Format(color.FgHiBlack, fmt.Sprintf("\n            %v\n", verificationURI)))
+	fmt.Printf("            Your code: %v\n\n", color.Format(color.Reset, color.Bold, da.UserCode))
+
+	a.wlm = waitlivemsg.New(nil)
+	a.msg = waitlivemsg.NewMessage("Waiting for authentication on another device [1/2]\n" +
+		fancy.Tip("^C to cancel"))
+	a.wlm.AddMessage(a.msg)
+	go a.wlm.Wait()
+	defer a.wlm.Stop()
+
+	token, err := df.Poll(ctx, da)
+	a.maybePrintReceivedToken(token)
+
+	if err != nil {
+		a.msg.StopText(fancy.Error("Authentication failed [1/2]"))
+		return err
+	}
+
+	wt, err := usertoken.ParseUnsignedJSONWebToken(token)
+
+	if err != nil {
+		a.msg.StopText(fancy.Error("Authentication failed [1/2]"))
+		return err
+	}
+
+	return a.saveUser(wt.Email, token)
+}
+
 func (a *Authentication) success(username string) {
 	var duration = a.wlm.Duration()
 	var conf = a.wectx.Config()
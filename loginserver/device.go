@@ -0,0 +1,152 @@
+package loginserver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/henvic/wedeploycli/apihelper"
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/verbose"
+)
+
+// DefaultDeviceInterval between polls, when not set by the server
+const DefaultDeviceInterval = 5 * time.Second
+
+// slowDownIncrease is added to the polling interval when the server asks to slow down
+const slowDownIncrease = 5 * time.Second
+
+var (
+	// ErrDeviceAccessDenied is used when the user denies the device authorization
+	ErrDeviceAccessDenied = errors.New("login denied on the other device")
+
+	// ErrDeviceCodeExpired is used when the device code expires before the user approves it
+	ErrDeviceCodeExpired = errors.New(`login code expired: try "lcp login" again`)
+)
+
+// DeviceAuthorization for logging in from another device
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceFlow logs in by approving a code on another device, without a browser on this machine.
+// See RFC 8628 (OAuth 2.0 Device Authorization Grant).
+type DeviceFlow struct {
+	Context config.Context
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Authorize requests a device and user code
+func (d *DeviceFlow) Authorize(ctx context.Context) (da DeviceAuthorization, err error) {
+	var apiClient = apihelper.New(d.Context)
+	var request = apiClient.URL(ctx, "/login/device")
+
+	if err := apihelper.Validate(request, request.Post()); err != nil {
+		return da, err
+	}
+
+	err = apihelper.DecodeJSON(request, &da)
+
+	if err == nil && (da.DeviceCode == "" || da.UserCode == "" || da.VerificationURI == "") {
+		err = errors.New("invalid device authorization response")
+	}
+
+	return da, err
+}
+
+// Poll until the user approves or denies the authorization on the other device, or the code expires
+func (d *DeviceFlow) Poll(ctx context.Context, da DeviceAuthorization) (token string, err error) {
+	var interval = time.Duration(da.Interval) * time.Second
+
+	if interval <= 0 {
+		interval = DefaultDeviceInterval
+	}
+
+	if da.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(da.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		if err := d.wait(ctx, interval); err != nil {
+			return "", expiredOr(ctx, err)
+		}
+
+		token, err := d.requestToken(ctx, da.DeviceCode)
+
+		if err == nil {
+			return token, nil
+		}
+
+		var af, ok = err.(apihelper.APIFault)
+
+		switch {
+		case ok && af.Has("authorization_pending"):
+			verbose.Debug("Device authorization is pending")
+		case ok && af.Has("slow_down"):
+			interval += slowDownIncrease
+			verbose.Debug("Device authorization polling interval increased to", interval)
+		case ok && af.Has("access_denied"):
+			return "", ErrDeviceAccessDenied
+		case ok && af.Has("expired_token"):
+			return "", ErrDeviceCodeExpired
+		default:
+			return "", expiredOr(ctx, err)
+		}
+	}
+}
+
+func (d *DeviceFlow) requestToken(ctx context.Context, deviceCode string) (string, error) {
+	var apiClient = apihelper.New(d.Context)
+	var request = apiClient.URL(ctx, "/login/device/token")
+
+	request.Form("device_code", deviceCode)
+
+	if err := apihelper.Validate(request, request.Post()); err != nil {
+		return "", err
+	}
+
+	var data accessToken
+
+	if err := apihelper.DecodeJSON(request, &data); err != nil {
+		return "", err
+	}
+
+	if data.AccessToken == "" {
+		return "", errors.New("no token received")
+	}
+
+	return data.AccessToken, nil
+}
+
+func (d *DeviceFlow) wait(ctx context.Context, interval time.Duration) error {
+	if d.sleep != nil {
+		return d.sleep(ctx, interval)
+	}
+
+	var t = time.NewTimer(interval)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// expiredOr returns ErrDeviceCodeExpired if the code has expired, or the error otherwise
+func expiredOr(ctx context.Context, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrDeviceCodeExpired
+	}
+
+	return err
+}
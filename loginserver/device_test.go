@@ -0,0 +1,183 @@
+package loginserver
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/servertest"
+)
+
+var wectx config.Context
+
+func TestMain(m *testing.M) {
+	var err error
+	wectx, err = config.Setup("mocks/.lcp")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := wectx.SetEndpoint(defaults.CloudRemote); err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func setupDeviceAuthServer() *servertest.DeviceAuthServer {
+	servertest.Setup()
+
+	var d = &servertest.DeviceAuthServer{
+		DeviceCode:      "device123",
+		UserCode:        "WDJB-MJHT",
+		VerificationURI: "https://console.liferay.cloud/device",
+		Token:           "token",
+		ExpiresIn:       600,
+		Interval:        5,
+	}
+
+	d.Register(servertest.Mux)
+	return d
+}
+
+func TestDeviceFlow(t *testing.T) {
+	var d = setupDeviceAuthServer()
+	defer servertest.Teardown()
+
+	d.SlowDown = 1
+
+	var waits []time.Duration
+
+	var df = &DeviceFlow{
+		Context: wectx,
+		sleep: func(ctx context.Context, interval time.Duration) error {
+			waits = append(waits, interval)
+
+			// the user approves the login after a few polls
+			if len(waits) == 3 {
+				d.SetStatus(servertest.DeviceApproved)
+			}
+
+			return nil
+		},
+	}
+
+	var da, err = df.Authorize(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error authorizing, got %v instead", err)
+	}
+
+	var want = DeviceAuthorization{
+		DeviceCode:              "device123",
+		UserCode:                "WDJB-MJHT",
+		VerificationURI:         "https://console.liferay.cloud/device",
+		VerificationURIComplete: "https://console.liferay.cloud/device?user_code=WDJB-MJHT",
+		ExpiresIn:               600,
+		Interval:                5,
+	}
+
+	if da != want {
+		t.Errorf("Wanted %+v, got %+v instead", want, da)
+	}
+
+	token, err := df.Poll(context.Background(), da)
+
+	if err != nil || token != "token" {
+		t.Errorf("Expected token, got %v (error: %v) instead", token, err)
+	}
+
+	var wantWaits = []time.Duration{5 * time.Second, 10 * time.Second, 10 * time.Second}
+
+	if len(waits) != len(wantWaits) {
+		t.Fatalf("Wanted waits %v, got %v instead", wantWaits, waits)
+	}
+
+	for i := range waits {
+		if waits[i] != wantWaits[i] {
+			t.Errorf("Wanted waits %v, got %v instead", wantWaits, waits)
+		}
+	}
+
+	if d.Polls() != 3 {
+		t.Errorf("Expected 3 polls, got %d instead", d.Polls())
+	}
+}
+
+func TestDeviceFlowFailures(t *testing.T) {
+	var cases = map[string]error{
+		servertest.DeviceDenied:  ErrDeviceAccessDenied,
+		servertest.DeviceExpired: ErrDeviceCodeExpired,
+	}
+
+	for status, want := range cases {
+		var d = setupDeviceAuthServer()
+
+		var df = &DeviceFlow{
+			Context: wectx,
+			sleep: func(ctx context.Context, interval time.Duration) error {
+				d.SetStatus(status)
+				return nil
+			},
+		}
+
+		var da, err = df.Authorize(context.Background())
+
+		if err != nil {
+			t.Fatalf("Expected no error authorizing, got %v instead", err)
+		}
+
+		if _, err := df.Poll(context.Background(), da); err != want {
+			t.Errorf("Wanted error %v for %v, got %v instead", want, status, err)
+		}
+
+		servertest.Teardown()
+	}
+}
+
+func TestDeviceFlowTimeout(t *testing.T) {
+	var d = setupDeviceAuthServer()
+	defer servertest.Teardown()
+
+	d.ExpiresIn = 1
+
+	var df = &DeviceFlow{
+		Context: wectx,
+		sleep: func(ctx context.Context, interval time.Duration) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	var da, err = df.Authorize(context.Background())
+
+	if err != nil {
+		t.Fatalf("Expected no error authorizing, got %v instead", err)
+	}
+
+	if _, err := df.Poll(context.Background(), da); err != ErrDeviceCodeExpired {
+		t.Errorf("Wanted error %v, got %v instead", ErrDeviceCodeExpired, err)
+	}
+}
+
+func TestDeviceFlowInvalidDeviceCode(t *testing.T) {
+	setupDeviceAuthServer()
+	defer servertest.Teardown()
+
+	var df = &DeviceFlow{
+		Context: wectx,
+		sleep: func(ctx context.Context, interval time.Duration) error {
+			return nil
+		},
+	}
+
+	var _, err = df.Poll(context.Background(), DeviceAuthorization{DeviceCode: "wrong"})
+
+	if err == nil || err == ErrDeviceCodeExpired {
+		t.Errorf("Expected invalid grant error, got %v instead", err)
+	}
+}
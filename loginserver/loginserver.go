@@ -2,6 +2,10 @@ package loginserver
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net"
@@ -22,11 +26,23 @@ import (
 // Service server for receiving JSON Web Token
 type Service struct {
 	Infrastructure string
-	ctx            context.Context
-	ctxCancel      context.CancelFunc
-	netListener    net.Listener
-	httpServer     *http.Server
-	serverAddress  string
+
+	// Context is used for exchanging an authorization code for a token
+	// when the identity provider supports PKCE
+	Context config.Context
+
+	ctx           context.Context
+	ctxCancel     context.CancelFunc
+	netListener   net.Listener
+	httpServer    *http.Server
+	serverAddress string
+
+	// state ties the callback to the login attempt that started it
+	state        string
+	codeVerifier string
+
+	m              sync.Mutex
+	exchanged      bool
 	temporaryToken string
 	jwt            usertoken.JSONWebToken
 	err            error
@@ -38,19 +54,34 @@ const redirectPage = `<html>
 <body>
 <form action="/authenticate" method="post" id="authenticate">
 <input type="hidden" id="access_token" name="access_token" />
+<input type="hidden" id="code" name="code" />
+<input type="hidden" id="state" name="state" />
 </form>
 <noscript>
 You need JavaScript enabled to complete the authentication. Enable it and try again.
 </noscript>
 <script>
-var accessToken = document.location.hash.replace('#access_token=', '');
-var rm = "#access_token=";
+function params(s) {
+	var p = {};
 
-if (accessToken.indexOf(rm) === 0) {
-	accessToken = accessToken.substr(rm.length)
+	s.replace(/^[#?]/, "").split("&").forEach(function (kv) {
+		var sep = kv.indexOf("=");
+
+		if (sep > 0) {
+			p[decodeURIComponent(kv.substr(0, sep))] = decodeURIComponent(kv.substr(sep + 1).replace(/\+/g, " "));
+		}
+	});
+
+	return p;
 }
 
-document.querySelector("#access_token").value = accessToken;
+var query = params(document.location.search);
+var fragment = params(document.location.hash);
+
+document.location.hash = "";
+document.querySelector("#access_token").value = fragment.access_token || "";
+document.querySelector("#code").value = query.code || fragment.code || "";
+document.querySelector("#state").value = query.state || fragment.state || "";
 document.querySelector("#authenticate").submit();
 </script>
 </body>
@@ -70,9 +101,43 @@ func (s *Service) Listen(ctx context.Context) (address string, err error) {
 			s.netListener.Addr().String(),
 			"127.0.0.1:"))
 
+	if s.state, err = randomString(); err != nil {
+		return "", errwrap.Wrapf("can't generate login state: {{err}}", err)
+	}
+
+	if s.codeVerifier, err = randomString(); err != nil {
+		return "", errwrap.Wrapf("can't generate PKCE code verifier: {{err}}", err)
+	}
+
 	return s.serverAddress, nil
 }
 
+// LoginURL for the given login page, with the state and PKCE code challenge of this login attempt
+func (s *Service) LoginURL(loginPage string) string {
+	var v = url.Values{}
+	v.Set("redirect_uri", s.serverAddress)
+	v.Set("state", s.state)
+	v.Set("code_challenge", codeChallenge(s.codeVerifier))
+	v.Set("code_challenge_method", "S256")
+	return loginPage + "?" + v.Encode()
+}
+
+// randomString of 256 bits, also suitable for a PKCE code verifier (RFC 7636)
+func randomString() (string, error) {
+	var b = make([]byte, 32)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func codeChallenge(verifier string) string {
+	var sum = sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 func (s *Service) waitServer(w *sync.WaitGroup) {
 	<-s.ctx.Done()
 	var err = s.httpServer.Shutdown(s.ctx)
@@ -125,6 +190,12 @@ func (s *Service) redirectToDashboard(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Service) httpHandler(w http.ResponseWriter, r *http.Request) {
+	// reject requests for other hosts (DNS rebinding)
+	if "http://"+r.Host != s.serverAddress {
+		safeErrorHandler(w, "403 Forbidden", http.StatusForbidden)
+		return
+	}
+
 	switch r.URL.Path {
 	case "/":
 		s.homeHandler(w, r)
@@ -156,14 +227,16 @@ func safeErrorHandler(w http.ResponseWriter, body string, code int) {
 }
 
 func (s *Service) homeHandler(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	referer, _ := url.Parse(r.Header.Get("Referer"))
 
 	// this is a compromise
 	var dashboard = defaults.DashboardAddressPrefix + s.Infrastructure
 	if referer.Host != "" && referer.Host != dashboard {
-		s.err = errors.New("token origin is not from given dashboard")
+		verbose.Debug("Rejecting authentication: token origin is not from given dashboard")
 		safeErrorHandler(w, "403 Forbidden", http.StatusForbidden)
-		s.ctxCancel()
 		return
 	}
 
@@ -175,35 +248,90 @@ var ErrSignUpEmailConfirmation = errors.New(`sign up on Liferay Cloud requested:
 
 const signupRequestPseudoToken = "signup_requested"
 
+// fromLocalhost checks if the request was made from the page served by the login service
+func (s *Service) fromLocalhost(r *http.Request) bool {
+	if origin := r.Header.Get("Origin"); origin != "" && origin != s.serverAddress {
+		return false
+	}
+
+	referer, err := url.Parse(r.Header.Get("Referer"))
+	return err == nil && referer.Scheme+"://"+referer.Host == s.serverAddress && referer.Path == "/"
+}
+
 func (s *Service) authenticateHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost || r.Header.Get("Referer") != s.serverAddress+"/" {
-		s.err = errors.New("authentication should have been POSTed and from a localhost origin")
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// bad requests are rejected without ending the login attempt,
+	// so that other local processes or web pages can't abort it
+	if s.exchanged {
+		verbose.Debug("Rejecting authentication: login attempt already used")
 		safeErrorHandler(w, "403 Forbidden", http.StatusForbidden)
-		s.ctxCancel()
 		return
 	}
 
-	var pferr = r.ParseForm()
+	if r.Method != http.MethodPost || !s.fromLocalhost(r) {
+		verbose.Debug("Rejecting authentication: should have been POSTed and from a localhost origin")
+		safeErrorHandler(w, "403 Forbidden", http.StatusForbidden)
+		return
+	}
 
-	if pferr != nil {
-		s.err = errwrap.Wrapf("can't parse authentication form: {{err}}", pferr)
+	if err := r.ParseForm(); err != nil {
+		verbose.Debug(fmt.Sprintf("Rejecting authentication: can't parse form: %v", err))
 		safeErrorHandler(w, "400 Bad Request", http.StatusBadRequest)
-		s.ctxCancel()
 		return
 	}
 
-	s.temporaryToken = r.Form.Get("access_token")
+	if subtle.ConstantTimeCompare([]byte(r.PostForm.Get("state")), []byte(s.state)) != 1 {
+		verbose.Debug("Rejecting authentication: login state mismatch")
+		safeErrorHandler(w, "403 Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// only a single exchange is allowed per login attempt
+	s.exchanged = true
+	defer s.ctxCancel()
+
+	s.temporaryToken, s.err = s.getToken(r.PostForm)
 	verbose.Debug("Access Token: " + verbose.SafeEscape(s.temporaryToken))
 
-	switch s.temporaryToken {
-	case signupRequestPseudoToken:
+	switch {
+	case s.err != nil:
+	case s.temporaryToken == signupRequestPseudoToken:
 		s.err = ErrSignUpEmailConfirmation
 	default:
 		s.jwt, s.err = usertoken.ParseUnsignedJSONWebToken(s.temporaryToken)
 	}
 
 	s.redirectToDashboard(w, r)
-	s.ctxCancel()
+}
+
+// getToken received directly or by exchanging an authorization code, if the identity provider supports PKCE
+func (s *Service) getToken(form url.Values) (string, error) {
+	var code = form.Get("code")
+
+	if code == "" {
+		return form.Get("access_token"), nil
+	}
+
+	var apiClient = apihelper.New(s.Context)
+	var request = apiClient.URL(s.ctx, "/login/token")
+
+	request.Form("code", code)
+	request.Form("code_verifier", s.codeVerifier)
+	request.Form("redirect_uri", s.serverAddress)
+
+	if err := apihelper.Validate(request, request.Post()); err != nil {
+		return "", errwrap.Wrapf("can't exchange authorization code: {{err}}", err)
+	}
+
+	var data accessToken
+
+	if err := apihelper.DecodeJSON(request, &data); err != nil {
+		return "", errwrap.Wrapf("can't exchange authorization code: {{err}}", err)
+	}
+
+	return data.AccessToken, nil
 }
 
 // Credentials for authenticated user or error, it blocks until the information is available
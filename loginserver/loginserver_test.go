@@ -0,0 +1,264 @@
+package loginserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/henvic/wedeploycli/servertest"
+)
+
+func listen(t *testing.T) (s *Service, loginURL *url.URL) {
+	s = &Service{
+		Infrastructure: "liferay.cloud",
+		Context:        wectx,
+	}
+
+	if _, err := s.Listen(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		s.ctxCancel()
+		_ = s.netListener.Close()
+	})
+
+	var err error
+	loginURL, err = url.Parse(s.LoginURL("https://console.liferay.cloud/login"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := loginURL.Query().Get("redirect_uri"); got != s.serverAddress {
+		t.Errorf("Wanted redirect_uri %v, got %v instead", s.serverAddress, got)
+	}
+
+	return s, loginURL
+}
+
+func signedToken(t *testing.T) string {
+	var s, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "admin@example.com",
+	}).SignedString([]byte("secret"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func authenticate(s *Service, form url.Values) *httptest.ResponseRecorder {
+	var r = httptest.NewRequest(http.MethodPost, s.serverAddress+"/authenticate", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Origin", s.serverAddress)
+	r.Header.Set("Referer", s.serverAddress+"/?code=foo&state=bar")
+
+	var w = httptest.NewRecorder()
+	s.httpHandler(w, r)
+	return w
+}
+
+func TestServiceAccessToken(t *testing.T) {
+	var s, loginURL = listen(t)
+	var token = signedToken(t)
+
+	var w = authenticate(s, url.Values{
+		"state":        []string{loginURL.Query().Get("state")},
+		"access_token": []string{token},
+	})
+
+	if w.Code != http.StatusSeeOther ||
+		w.Header().Get("Location") != "https://console.liferay.cloud/static/cli/login-success/" {
+		t.Errorf("Expected redirect to success page, got %v (%v) instead", w.Code, w.Header().Get("Location"))
+	}
+
+	username, got, err := s.Credentials()
+
+	if username != "admin@example.com" || got != token || err != nil {
+		t.Errorf("Expected credentials, got (%v, %v, %v) instead", username, got, err)
+	}
+}
+
+func TestServiceStateMismatch(t *testing.T) {
+	var cases = []url.Values{
+		{},
+		{"state": []string{"wrong"}},
+	}
+
+	for _, form := range cases {
+		var s, loginURL = listen(t)
+		form.Set("access_token", "injected")
+
+		if w := authenticate(s, form); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code to be 403, got %v instead", w.Code)
+		}
+
+		expectLoginAttemptNotEnded(t, s, loginURL)
+	}
+}
+
+// expectLoginAttemptNotEnded by a rejected request, and that it still accepts the right callback
+func expectLoginAttemptNotEnded(t *testing.T, s *Service, loginURL *url.URL) {
+	if s.ctx.Err() != nil {
+		t.Errorf("Expected login attempt to continue after a rejected request, got %v instead", s.ctx.Err())
+	}
+
+	var token = signedToken(t)
+
+	var w = authenticate(s, url.Values{
+		"state":        []string{loginURL.Query().Get("state")},
+		"access_token": []string{token},
+	})
+
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("Expected status code to be 303, got %v instead", w.Code)
+	}
+
+	if _, got, err := s.Credentials(); got != token || err != nil {
+		t.Errorf("Expected token, got %v (error: %v) instead", got, err)
+	}
+}
+
+func TestServiceSingleExchange(t *testing.T) {
+	var s, loginURL = listen(t)
+	var token = signedToken(t)
+
+	var form = url.Values{
+		"state":        []string{loginURL.Query().Get("state")},
+		"access_token": []string{token},
+	}
+
+	if w := authenticate(s, form); w.Code != http.StatusSeeOther {
+		t.Errorf("Expected status code to be 303, got %v instead", w.Code)
+	}
+
+	form.Set("access_token", "injected")
+
+	if w := authenticate(s, form); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code to be 403, got %v instead", w.Code)
+	}
+
+	if _, got, err := s.Credentials(); got != token || err != nil {
+		t.Errorf("Expected first token to be kept, got %v (error: %v) instead", got, err)
+	}
+}
+
+func TestServiceRejectsForeignRequests(t *testing.T) {
+	var cases = []func(r *http.Request){
+		func(r *http.Request) {
+			r.Header.Set("Origin", "https://example.com")
+		},
+		func(r *http.Request) {
+			r.Header.Set("Referer", "https://example.com/")
+		},
+		func(r *http.Request) {
+			r.Header.Del("Referer")
+		},
+		func(r *http.Request) {
+			r.Method = http.MethodGet
+		},
+	}
+
+	for _, c := range cases {
+		var s, loginURL = listen(t)
+
+		var form = url.Values{
+			"state":        []string{loginURL.Query().Get("state")},
+			"access_token": []string{signedToken(t)},
+		}
+
+		var r = httptest.NewRequest(http.MethodPost, s.serverAddress+"/authenticate", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Origin", s.serverAddress)
+		r.Header.Set("Referer", s.serverAddress+"/")
+		c(r)
+
+		var w = httptest.NewRecorder()
+		s.httpHandler(w, r)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code to be 403, got %v instead", w.Code)
+		}
+
+		expectLoginAttemptNotEnded(t, s, loginURL)
+	}
+}
+
+func TestServiceRejectsOtherHosts(t *testing.T) {
+	var s, loginURL = listen(t)
+
+	var r = httptest.NewRequest(http.MethodGet, "http://attacker.example.com/", nil)
+	var w = httptest.NewRecorder()
+	s.httpHandler(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code to be 403, got %v instead", w.Code)
+	}
+
+	expectLoginAttemptNotEnded(t, s, loginURL)
+}
+
+func TestServicePKCE(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var s, loginURL = listen(t)
+	var token = signedToken(t)
+	var query = loginURL.Query()
+
+	if query.Get("code_challenge_method") != "S256" {
+		t.Errorf("Expected code challenge method to be S256, got %v instead", query.Get("code_challenge_method"))
+	}
+
+	servertest.Mux.HandleFunc("/login/token", func(w http.ResponseWriter, r *http.Request) {
+		var sum = sha256.Sum256([]byte(r.FormValue("code_verifier")))
+
+		if r.Method != http.MethodPost ||
+			r.FormValue("code") != "abc" ||
+			r.FormValue("redirect_uri") != s.serverAddress ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != query.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		_, _ = fmt.Fprintf(w, `{"token": "%s"}`, token)
+	})
+
+	var w = authenticate(s, url.Values{
+		"state": []string{query.Get("state")},
+		"code":  []string{"abc"},
+	})
+
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("Expected status code to be 303, got %v instead", w.Code)
+	}
+
+	username, got, err := s.Credentials()
+
+	if username != "admin@example.com" || got != token || err != nil {
+		t.Errorf("Expected credentials, got (%v, %v, %v) instead", username, got, err)
+	}
+}
+
+func TestServiceStateIsRandom(t *testing.T) {
+	var _, first = listen(t)
+	var _, second = listen(t)
+
+	if first.Query().Get("state") == second.Query().Get("state") {
+		t.Errorf("Expected state to be different for each login attempt")
+	}
+
+	if len(first.Query().Get("state")) != 43 {
+		t.Errorf("Expected state to have 256 bits, got %v instead", first.Query().Get("state"))
+	}
+}
@@ -0,0 +1,251 @@
+package logfiles
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/logs"
+	"github.com/henvic/wedeploycli/verbose"
+)
+
+// Writer for exporting logs to files split per service and per day (UTC).
+// Files are written to <dir>/<project>/<service>/<yyyy-mm-dd>.<n>.log
+type Writer struct {
+	Dir string
+
+	// RotateSize is the size in bytes after which a new segment is started.
+	// Use zero to rotate only when the day changes.
+	RotateSize int64
+
+	// Compress finished segments with gzip.
+	Compress bool
+
+	segments map[string]*segment
+	m        sync.Mutex
+}
+
+// projectLevel is used as the service directory for logs not related to a service.
+const projectLevel = "_project"
+
+type segment struct {
+	day  string
+	path string
+	file *os.File
+	size int64
+}
+
+// Write log lines.
+func (w *Writer) Write(list []logs.Log) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.segments == nil {
+		w.segments = map[string]*segment{}
+	}
+
+	for _, l := range list {
+		if err := w.write(l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) write(l logs.Log) error {
+	var t = time.Time(l.Timestamp).UTC()
+	var service = l.ServiceID
+
+	if service == "" {
+		service = projectLevel
+	}
+
+	var key = filepath.Join(l.ProjectID, service)
+	var day = t.Format("2006-01-02")
+	var s, err = w.getSegment(key, day)
+
+	if err != nil {
+		return err
+	}
+
+	var line = fmt.Sprintf("%s %s %s %s\n",
+		t.Format(time.RFC3339Nano),
+		getSource(l),
+		getLevel(l),
+		strings.TrimSpace(l.Message))
+
+	n, err := io.WriteString(s.file, line)
+	s.size += int64(n)
+
+	if err != nil {
+		return errwrap.Wrapf("can't write log line: {{err}}", err)
+	}
+
+	return nil
+}
+
+func getSource(l logs.Log) string {
+	switch {
+	case l.ContainerUID != "":
+		return l.ContainerUID
+	case l.Build:
+		return "build-" + l.BuildGroupUID
+	}
+
+	return "-"
+}
+
+func getLevel(l logs.Log) string {
+	if l.Level == "" {
+		return "-"
+	}
+
+	return l.Level
+}
+
+func (w *Writer) getSegment(key, day string) (*segment, error) {
+	var s, ok = w.segments[key]
+
+	switch {
+	case !ok:
+	case s.day != day:
+		verbose.Debug("Day changed for " + key + ": finishing segment " + s.path)
+		fallthrough
+	case w.RotateSize != 0 && s.size >= w.RotateSize:
+		if err := w.finish(s); err != nil {
+			return nil, err
+		}
+	default:
+		return s, nil
+	}
+
+	s, err := w.newSegment(key, day)
+
+	if err != nil {
+		return nil, err
+	}
+
+	w.segments[key] = s
+	return s, nil
+}
+
+func (w *Writer) newSegment(key, day string) (*segment, error) {
+	var dir = filepath.Join(w.Dir, key)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errwrap.Wrapf("can't create logs directory: {{err}}", err)
+	}
+
+	for n := 0; ; n++ {
+		var p = filepath.Join(dir, fmt.Sprintf("%s.%d.log", day, n))
+
+		if exists(p) || exists(p+".gz") {
+			continue
+		}
+
+		file, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // #nosec
+
+		if err != nil {
+			return nil, errwrap.Wrapf("can't create log file: {{err}}", err)
+		}
+
+		verbose.Debug("Writing logs to " + p)
+
+		return &segment{
+			day:  day,
+			path: p,
+			file: file,
+		}, nil
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (w *Writer) finish(s *segment) error {
+	if err := s.file.Close(); err != nil {
+		return errwrap.Wrapf("can't close log file: {{err}}", err)
+	}
+
+	if !w.Compress {
+		return nil
+	}
+
+	return compress(s.path)
+}
+
+func compress(path string) error {
+	src, err := os.Open(path) // #nosec
+
+	if err != nil {
+		return errwrap.Wrapf("can't open log file for compression: {{err}}", err)
+	}
+
+	defer func() {
+		_ = src.Close()
+	}()
+
+	var tmp = path + ".gz.tmp"
+
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) // #nosec
+
+	if err != nil {
+		return errwrap.Wrapf("can't create compressed log file: {{err}}", err)
+	}
+
+	var gz = gzip.NewWriter(dst)
+	gz.Name = filepath.Base(path)
+
+	_, err = io.Copy(gz, src)
+
+	if ec := gz.Close(); err == nil {
+		err = ec
+	}
+
+	if ec := dst.Close(); err == nil {
+		err = ec
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp)
+		return errwrap.Wrapf("can't compress log file: {{err}}", err)
+	}
+
+	if err = os.Rename(tmp, path+".gz"); err != nil {
+		return errwrap.Wrapf("can't rename compressed log file: {{err}}", err)
+	}
+
+	return os.Remove(path)
+}
+
+// Close finishes all open segments.
+func (w *Writer) Close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	var errs []string
+
+	for key, s := range w.segments {
+		if err := w.finish(s); err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		delete(w.segments, key)
+	}
+
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
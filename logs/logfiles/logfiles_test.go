@@ -0,0 +1,181 @@
+package logfiles
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/logs"
+	"github.com/henvic/wedeploycli/logs/internal/timelog"
+)
+
+func getTime(t *testing.T, value string) timelog.TimeStackDriver {
+	var v, err = time.Parse(time.RFC3339Nano, value)
+
+	if err != nil {
+		t.Fatalf("Cannot parse time: %v", err)
+	}
+
+	return timelog.TimeStackDriver(v)
+}
+
+func createTempDir(t *testing.T) string {
+	var dir, err = ioutil.TempDir(os.TempDir(), "lcp-logfiles")
+
+	if err != nil {
+		t.Fatalf("Cannot create temporary directory: %v", err)
+	}
+
+	return dir
+}
+
+func readFile(t *testing.T, path string) string {
+	var b, err = ioutil.ReadFile(path)
+
+	if err != nil {
+		t.Errorf("Cannot read file: %v", err)
+	}
+
+	return string(b)
+}
+
+func readGzipFile(t *testing.T, path string) string {
+	var f, err = os.Open(path)
+
+	if err != nil {
+		t.Fatalf("Cannot open file: %v", err)
+	}
+
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+
+	if err != nil {
+		t.Fatalf("Cannot read gzip file: %v", err)
+	}
+
+	b, err := ioutil.ReadAll(gz)
+
+	if err != nil {
+		t.Errorf("Cannot read gzip content: %v", err)
+	}
+
+	return string(b)
+}
+
+func TestWriterSplitPerServiceAndDay(t *testing.T) {
+	var dir = createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var w = &Writer{
+		Dir: dir,
+	}
+
+	var list = []logs.Log{
+		{
+			ProjectID:    "acme-prd",
+			ServiceID:    "liferay",
+			ContainerUID: "liferay-1",
+			Level:        "INFO",
+			Message:      "Server started\r",
+			Timestamp:    getTime(t, "2019-06-03T23:59:59.5Z"),
+		},
+		{
+			ProjectID: "acme-prd",
+			Message:   "Deploying project",
+			Timestamp: getTime(t, "2019-06-03T23:59:59.6Z"),
+		},
+		{
+			ProjectID:    "acme-prd",
+			ServiceID:    "liferay",
+			ContainerUID: "liferay-1",
+			Level:        "ERROR",
+			Message:      "Out of memory",
+			Timestamp:    getTime(t, "2019-06-04T00:00:01Z"),
+		},
+	}
+
+	if err := w.Write(list); err != nil {
+		t.Errorf("Expected no error writing logs, got %v instead", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected no error closing writer, got %v instead", err)
+	}
+
+	var cases = map[string]string{
+		"acme-prd/liferay/2019-06-03.0.log":  "2019-06-03T23:59:59.5Z liferay-1 INFO Server started\n",
+		"acme-prd/liferay/2019-06-04.0.log":  "2019-06-04T00:00:01Z liferay-1 ERROR Out of memory\n",
+		"acme-prd/_project/2019-06-03.0.log": "2019-06-03T23:59:59.6Z - - Deploying project\n",
+	}
+
+	for path, want := range cases {
+		if got := readFile(t, filepath.Join(dir, path)); got != want {
+			t.Errorf("Expected %v to be %q, got %q instead", path, want, got)
+		}
+	}
+}
+
+func TestWriterRotateAndCompress(t *testing.T) {
+	var dir = createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var w = &Writer{
+		Dir:        dir,
+		RotateSize: 10,
+		Compress:   true,
+	}
+
+	var l = logs.Log{
+		ProjectID:    "acme-prd",
+		ServiceID:    "search",
+		ContainerUID: "search-1",
+		Level:        "INFO",
+		Message:      "indexing",
+		Timestamp:    getTime(t, "2019-06-03T10:00:00Z"),
+	}
+
+	if err := w.Write([]logs.Log{l, l}); err != nil {
+		t.Errorf("Expected no error writing logs, got %v instead", err)
+	}
+
+	var current = filepath.Join(dir, "acme-prd/search/2019-06-03.1.log")
+
+	if _, err := os.Stat(current); err != nil {
+		t.Errorf("Expected current segment to be open, got %v instead", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected no error closing writer, got %v instead", err)
+	}
+
+	var want = "2019-06-03T10:00:00Z search-1 INFO indexing\n"
+
+	for _, path := range []string{"2019-06-03.0.log.gz", "2019-06-03.1.log.gz"} {
+		var p = filepath.Join(dir, "acme-prd/search", path)
+
+		if got := readGzipFile(t, p); got != want {
+			t.Errorf("Expected %v to be %q, got %q instead", path, want, got)
+		}
+	}
+
+	if _, err := os.Stat(current); !os.IsNotExist(err) {
+		t.Errorf("Expected uncompressed segment to be removed, got %v instead", err)
+	}
+
+	// resuming the export shouldn't overwrite existing segments
+	if err := w.Write([]logs.Log{l}); err != nil {
+		t.Errorf("Expected no error writing logs, got %v instead", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected no error closing writer, got %v instead", err)
+	}
+
+	if got := readGzipFile(t, filepath.Join(dir, "acme-prd/search/2019-06-03.2.log.gz")); got != want {
+		t.Errorf("Expected new segment to be %q, got %q instead", want, got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -63,7 +64,16 @@ type Watcher struct {
 	Client          *Client
 	PoolingInterval time.Duration
 
-	Filter      *Filter
+	Filter *Filter
+
+	// Filters is used instead of Filter to follow logs from many projects at once.
+	// Log lines are merged in timestamp order and prefixed by their project.
+	Filters []*Filter
+
+	// Handler receives new log lines instead of them being printed (i.e., to export them).
+	// If it fails, watching stops and the log lines are not skipped.
+	Handler func(list []Log) error
+
 	filterMutex sync.Mutex
 
 	ctx context.Context
@@ -73,6 +83,7 @@ type Watcher struct {
 var PoolingInterval = 5 * time.Second
 
 var instancesWheel = colorwheel.New(color.TextPalette)
+var projectsWheel = colorwheel.New(color.TextPalette)
 
 var errStream io.Writer = os.Stderr
 var errStreamMutex sync.Mutex
@@ -166,19 +177,72 @@ func isService(current string, ss []string) bool {
 	return len(ss) == 0
 }
 
+// GetMergedList gets logs for multiple filters (i.e., projects) merged in timestamp order.
+func (c *Client) GetMergedList(ctx context.Context, filters []*Filter) ([]Log, error) {
+	var lists = make([][]Log, len(filters))
+	var errs = make([]error, len(filters))
+
+	var wg sync.WaitGroup
+
+	for i, f := range filters {
+		wg.Add(1)
+
+		go func(i int, f *Filter) {
+			defer wg.Done()
+			lists[i], errs[i] = c.GetList(ctx, f)
+		}(i, f)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errwrap.Wrapf(fmt.Sprintf("can't get logs for project %s: {{err}}", filters[i].Project), err)
+		}
+	}
+
+	return merge(lists), nil
+}
+
+func merge(lists [][]Log) []Log {
+	var m = []Log{}
+
+	for _, l := range lists {
+		m = append(m, l...)
+	}
+
+	sort.SliceStable(m, func(i, j int) bool {
+		return time.Time(m[i].Timestamp).Before(time.Time(m[j].Timestamp))
+	})
+
+	return m
+}
+
 // List logs
 func (c *Client) List(ctx context.Context, filter *Filter) error {
 	var list, err = c.GetList(ctx, filter)
 
 	if err == nil {
-		printList(list)
+		printList(list, false)
+	}
+
+	return err
+}
+
+// ListMerged lists logs for multiple filters (i.e., projects) merged in timestamp order.
+func (c *Client) ListMerged(ctx context.Context, filters []*Filter) error {
+	var list, err = c.GetMergedList(ctx, filters)
+
+	if err == nil {
+		printList(list, true)
 	}
 
 	return err
 }
 
 // Watch logs. If no pooling interval is set it uses the default value.
-func (w *Watcher) Watch(ctx context.Context, wectx config.Context) {
+// It returns when the context is canceled, or with an error if the handler fails.
+func (w *Watcher) Watch(ctx context.Context, wectx config.Context) error {
 	w.ctx = ctx
 	w.Client = New(wectx)
 
@@ -186,22 +250,29 @@ func (w *Watcher) Watch(ctx context.Context, wectx config.Context) {
 		w.PoolingInterval = PoolingInterval
 	}
 
-	w.watch()
+	return w.watch()
 }
 
-func (w *Watcher) watch() {
-	_, _ = fmt.Fprintf(outStream, "Logs shown on your current timezone: %s\n", time.Now().Format("-07:00"))
-	w.pool()
+func (w *Watcher) watch() error {
+	if w.Handler == nil {
+		_, _ = fmt.Fprintf(outStream, "Logs shown on your current timezone: %s\n", time.Now().Format("-07:00"))
+	}
+
+	if err := w.pool(); err != nil {
+		return err
+	}
 
 	ticker := time.NewTicker(w.PoolingInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-w.ctx.Done():
-			ticker.Stop()
-			return
+			return nil
 		case <-ticker.C:
-			w.pool()
+			if err := w.pool(); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -221,12 +292,17 @@ func addHeader(log Log) (m string) {
 	return "[" + log.ProjectID + "]"
 }
 
-func printList(list []Log) {
+func printList(list []Log, withProject bool) {
 	for _, log := range list {
 		iw := instancesWheel.Get(log.ProjectID + "-" + log.ContainerUID)
 		fd := color.Format(iw, addHeader(log))
 		ts := color.Format(color.FgWhite, getLocalTimestamp(log.Timestamp))
 
+		if withProject {
+			pw := projectsWheel.Get(log.ProjectID)
+			fd = color.Format(pw, log.ProjectID) + " " + fd
+		}
+
 		outStreamMutex.Lock()
 		_, _ = fmt.Fprintf(outStream, "%v %v %v\n", ts, fd, strings.TrimSpace(log.Message))
 		outStreamMutex.Unlock()
@@ -239,43 +315,91 @@ func getLocalTimestamp(t timelog.TimeStackDriver) string {
 	return l.Format("Jan 02 15:04:05.000")
 }
 
-func (w *Watcher) pool() {
+func (w *Watcher) filters() []*Filter {
+	if len(w.Filters) != 0 {
+		return w.Filters
+	}
+
+	return []*Filter{w.Filter}
+}
+
+func (w *Watcher) pool() error {
+	var filters = w.filters()
+	var lists = make([][]Log, len(filters))
+	var wg sync.WaitGroup
+
+	for i, f := range filters {
+		wg.Add(1)
+
+		go func(i int, f *Filter) {
+			defer wg.Done()
+			lists[i] = w.poolFilter(f)
+		}(i, f)
+	}
+
+	wg.Wait()
+
+	var list = merge(lists)
+
+	switch {
+	case w.Handler == nil:
+		printList(list, len(w.Filters) != 0)
+	case len(list) == 0:
+		return nil
+	default:
+		if err := w.Handler(list); err != nil {
+			return err
+		}
+	}
+
+	// only move forward once the log lines are handled, so they are not skipped
+	for i, f := range filters {
+		w.maybePrepareNext(f, lists[i])
+	}
+
+	return nil
+}
+
+func (w *Watcher) poolFilter(f *Filter) []Log {
 	var ctx, cancel = context.WithTimeout(w.ctx, 10*time.Second)
 	defer cancel()
 
-	var list, err = w.Client.GetList(ctx, w.Filter)
+	w.filterMutex.Lock()
+	var fc = *f
+	w.filterMutex.Unlock()
+
+	var list, err = w.Client.GetList(ctx, &fc)
 	cancel()
 
 	if err != nil && w.ctx.Err() == nil {
 		errStreamMutex.Lock()
 		defer errStreamMutex.Unlock()
 		_, _ = fmt.Fprintf(errStream, "%v\n", errorhandler.Handle(err))
-		return
+		return nil
 	}
 
 	if len(list) == 0 {
-		w.filterMutex.Lock()
-		defer w.filterMutex.Unlock()
-		verbose.Debug("No new log since " + w.Filter.Since)
-		return
+		verbose.Debug("No new log for " + fc.Project + " since " + fc.Since)
 	}
 
-	printList(list)
+	return list
+}
 
-	if err := w.prepareNext(list); err != nil {
+func (w *Watcher) maybePrepareNext(f *Filter, list []Log) {
+	if len(list) == 0 {
+		return
+	}
+
+	if err := w.prepareNext(f, list); err != nil {
 		errStreamMutex.Lock()
 		defer errStreamMutex.Unlock()
 		_, _ = fmt.Fprintf(errStream, "%v\n", errorhandler.Handle(err))
-		return
 	}
 }
 
-func (w *Watcher) prepareNext(list []Log) error {
+func (w *Watcher) prepareNext(f *Filter, list []Log) error {
 	var last = list[len(list)-1]
 
-	w.Filter.AfterInsertID = last.InsertID
-	verbose.Debug("Next logs after log insertId = " + last.InsertID)
-
 	var next = time.Time(last.Timestamp)
 
 	if next.IsZero() {
@@ -284,8 +408,10 @@ func (w *Watcher) prepareNext(list []Log) error {
 
 	w.filterMutex.Lock()
 	defer w.filterMutex.Unlock()
-	w.Filter.Since = fmt.Sprintf("%v", next.Add(time.Nanosecond).Format(time.RFC3339Nano))
-	verbose.Debug("Next --since parameter value = " + w.Filter.Since)
+	f.AfterInsertID = last.InsertID
+	verbose.Debug("Next logs after log insertId = " + last.InsertID)
+	f.Since = fmt.Sprintf("%v", next.Add(time.Nanosecond).Format(time.RFC3339Nano))
+	verbose.Debug("Next --since parameter value = " + f.Since)
 	return nil
 }
 
@@ -309,6 +435,22 @@ func GetUnixTimestamp(since string) (int64, error) {
 	return now.Add(-pds).Unix(), err
 }
 
+// ParseSince gets the value for Filter.Since from a friendly string
+func ParseSince(since string) (string, error) {
+	if since == "" {
+		return "", nil
+	}
+
+	t, err := GetUnixTimestamp(since)
+
+	if err != nil {
+		return "", errwrap.Wrapf("can't parse since argument: {{err}}.", err)
+	}
+
+	// use nanoseconds instead of seconds (console takes ns as a param)
+	return fmt.Sprintf("%v000000000", t), err
+}
+
 func trim(s string, max int) string {
 	runes := []rune(s)
 
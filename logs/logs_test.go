@@ -3,10 +3,12 @@ package logs
 import (
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -195,6 +197,159 @@ func TestListProject(t *testing.T) {
 	servertest.Teardown()
 }
 
+func TestListMerged(t *testing.T) {
+	outStreamMutex.Lock()
+	var defaultOutStream = outStream
+	outStream = &bufOutStream
+	bufOutStream.Reset()
+	outStreamMutex.Unlock()
+
+	var defaultNoColor = color.NoColor
+	color.NoColor = true
+
+	servertest.Setup()
+
+	servertest.Mux.HandleFunc("/projects/acme-dev/logs",
+		tdata.ServerJSONFileHandler("mocks/logs_merged_response_dev.json"))
+	servertest.Mux.HandleFunc("/projects/acme-uat/logs",
+		tdata.ServerJSONFileHandler("mocks/logs_merged_response_uat.json"))
+
+	var filters = []*Filter{
+		{
+			Project: "acme-dev",
+		},
+		{
+			Project: "acme-uat",
+		},
+	}
+
+	var err = New(wectx).ListMerged(context.Background(), filters)
+
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	outStreamMutex.Lock()
+	var got = bufOutStream.String()
+	outStreamMutex.Unlock()
+
+	if update {
+		tdata.ToFile("mocks/logs_merged_print", got)
+	}
+
+	var want = tdata.FromFile("mocks/logs_merged_print")
+
+	stringlib.AssertSimilar(t, want, got)
+
+	color.NoColor = defaultNoColor
+	outStreamMutex.Lock()
+	outStream = defaultOutStream
+	outStreamMutex.Unlock()
+
+	servertest.Teardown()
+}
+
+func TestGetMergedListFailure(t *testing.T) {
+	servertest.Setup()
+
+	servertest.Mux.HandleFunc("/projects/acme-dev/logs",
+		tdata.ServerJSONFileHandler("mocks/logs_merged_response_dev.json"))
+
+	var filters = []*Filter{
+		{
+			Project: "acme-dev",
+		},
+		{
+			Project: "acme-prd",
+		},
+	}
+
+	var _, err = New(wectx).GetMergedList(context.Background(), filters)
+
+	if err == nil || !strings.Contains(err.Error(), "can't get logs for project acme-prd") {
+		t.Errorf("Expected error for missing project, got %v instead", err)
+	}
+
+	servertest.Teardown()
+}
+
+func TestWatchMerged(t *testing.T) {
+	outStreamMutex.Lock()
+	var defaultOutStream = outStream
+	outStream = &bufOutStream
+	bufOutStream.Reset()
+	outStreamMutex.Unlock()
+
+	var defaultNoColor = color.NoColor
+	color.NoColor = true
+
+	servertest.Setup()
+
+	var served = map[string]bool{}
+	var servedM sync.Mutex
+
+	var handler = func(project string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			servedM.Lock()
+			defer servedM.Unlock()
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+			if served[project] {
+				if r.URL.Query().Get("afterInsertId") != project+"-2" {
+					t.Errorf("Wrong value for afterInsertId on %s", project)
+				}
+
+				_, _ = fmt.Fprintln(w, "[]")
+				return
+			}
+
+			served[project] = true
+			_, _ = fmt.Fprintln(w, tdata.FromFile("mocks/logs_merged_response_"+project+".json"))
+		}
+	}
+
+	servertest.Mux.HandleFunc("/projects/acme-dev/logs", handler("dev"))
+	servertest.Mux.HandleFunc("/projects/acme-uat/logs", handler("uat"))
+
+	var w = &Watcher{
+		Filters: []*Filter{
+			{
+				Project: "acme-dev",
+			},
+			{
+				Project: "acme-uat",
+			},
+		},
+		PoolingInterval: time.Millisecond,
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w.Watch(ctx, wectx)
+
+	outStreamMutex.Lock()
+	var got = bufOutStream.String()
+	outStreamMutex.Unlock()
+
+	if update {
+		tdata.ToFile("mocks/logs_watch_merged", got)
+	}
+
+	var want = tdata.FromFile("mocks/logs_watch_merged")
+
+	stringlib.AssertSimilar(t, want, got)
+
+	// some time before cleaning up services on other goroutines...
+	time.Sleep(10 * time.Millisecond)
+	color.NoColor = defaultNoColor
+	outStreamMutex.Lock()
+	outStream = defaultOutStream
+	outStreamMutex.Unlock()
+	servertest.Teardown()
+}
+
 func TestWatch(t *testing.T) {
 	outStreamMutex.Lock()
 	var defaultOutStream = outStream
@@ -413,3 +568,80 @@ func TestGetUnixTimestampParseError(t *testing.T) {
 		t.Errorf("Wanted parsing error, got %v instead", err)
 	}
 }
+
+func TestParseSince(t *testing.T) {
+	var cases = map[string]string{
+		"":           "",
+		"1470422556": "1470422556000000000",
+	}
+
+	for since, want := range cases {
+		if got, err := ParseSince(since); got != want || err != nil {
+			t.Errorf("Wanted %v for %v, got %v (error: %v) instead", want, since, got, err)
+		}
+	}
+
+	if _, err := ParseSince("dog"); err == nil || !strings.HasPrefix(err.Error(), "can't parse since argument: ") {
+		t.Errorf("Wanted parsing error, got %v instead", err)
+	}
+}
+
+func TestWatchHandlerFailure(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var requests = 0
+	var m sync.Mutex
+
+	servertest.Mux.HandleFunc("/projects/foo/logs",
+		func(w http.ResponseWriter, r *http.Request) {
+			m.Lock()
+			defer m.Unlock()
+
+			if requests < 2 {
+				requests++
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			_, _ = fmt.Fprintln(w, tdata.FromFile(fmt.Sprintf("mocks/logs_watch_response_%d.json", requests)))
+		})
+
+	var f = &Filter{
+		Project: "foo",
+	}
+
+	var calls = 0
+	var since string
+	var errWrite = errors.New("no space left on device")
+
+	var w = &Watcher{
+		Filter:          f,
+		PoolingInterval: time.Millisecond,
+		Handler: func(list []Log) error {
+			calls++
+
+			if calls == 1 {
+				return nil
+			}
+
+			// the cursor is moved forward only after the first log lines are handled
+			since = f.Since
+			return errWrite
+		},
+	}
+
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := w.Watch(ctx, wectx); err != errWrite {
+		t.Errorf("Expected handler error to stop watching, got %v instead", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected handler to be called twice, got %d instead", calls)
+	}
+
+	if since == "" || f.Since != since {
+		t.Errorf("Expected cursor to stay at %v after the handler failed, got %v instead", since, f.Since)
+	}
+}
@@ -0,0 +1,229 @@
+// Package portforward tunnels local TCP connections to a port on a service instance.
+//
+// Each connection runs a relay on the instance through the exec channel.
+// The channel only carries text, so data is sent as base64 encoded lines on both directions.
+package portforward
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/shell"
+	"github.com/henvic/wedeploycli/verbose"
+)
+
+// chunkSize is the maximum number of bytes sent on each line
+const chunkSize = 16 * 1024
+
+// relayScript decodes lines from stdin to the port, and encodes whatever is read from it as lines.
+// dd returns on partial reads, so each chunk is sent as soon as it is available.
+const relayScript = `command -v nc >/dev/null 2>&1 || { echo "port forwarding requires nc on the instance" >&2; exit 127; }
+while IFS= read -r l; do printf '%s' "$l" | base64 -d; done | nc 127.0.0.1 "$1" |
+while :; do c=$(dd bs=16384 count=1 2>/dev/null | base64 | tr -d '\n'); [ -z "$c" ] && break; echo "$c"; done`
+
+// Ports to forward
+type Ports struct {
+	Local  int
+	Remote int
+}
+
+// ParsePorts from a "local:remote" or "port" value
+func ParsePorts(s string) (p Ports, err error) {
+	var parts = strings.Split(s, ":")
+
+	if len(parts) > 2 {
+		return p, fmt.Errorf(`invalid ports "%s": use <local port>:<remote port>`, s)
+	}
+
+	if p.Local, err = parsePort(parts[0]); err != nil {
+		return p, err
+	}
+
+	p.Remote = p.Local
+
+	if len(parts) == 2 {
+		p.Remote, err = parsePort(parts[1])
+	}
+
+	return p, err
+}
+
+func parsePort(s string) (int, error) {
+	var port, err = strconv.Atoi(s)
+
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf(`invalid port "%s"`, s)
+	}
+
+	return port, nil
+}
+
+// RunFunc runs the relay on the instance, with the given stdin and stdout
+type RunFunc func(ctx context.Context, stdin io.Reader, stdout io.Writer) error
+
+// Exec returns a RunFunc that runs the relay for a remote port through the exec channel
+func Exec(params shell.Params, remotePort int) RunFunc {
+	return func(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+		var p = params
+		p.AttachStdin = true
+		p.TTY = false
+		p.Stdin = stdin
+		p.Stdout = stdout
+
+		var err = shell.Run(ctx, p, "sh", "-c", relayScript, "sh", strconv.Itoa(remotePort))
+
+		if ee, ok := err.(*shell.ExitError); ok {
+			if code, ok := ee.GetExitCode(); ok && code == 0 {
+				return nil
+			}
+		}
+
+		return err
+	}
+}
+
+// Forwarder of local connections
+type Forwarder struct {
+	Run RunFunc
+
+	// Status is called when connections are opened or closed, if not nil
+	Status func(conn net.Conn, err error, closed bool)
+}
+
+// Serve connections from a listener until the context is canceled
+func (f *Forwarder) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := l.Accept()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			f.status(conn, nil, false)
+			f.status(conn, Relay(ctx, conn, f.Run), true)
+		}()
+	}
+}
+
+func (f *Forwarder) status(conn net.Conn, err error, closed bool) {
+	if f.Status != nil {
+		f.Status(conn, err, closed)
+	}
+}
+
+// Relay a connection through the relay running on the instance
+func Relay(ctx context.Context, conn net.Conn, run RunFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	var pr, pw = io.Pipe()
+
+	go func() {
+		// the relay is stopped once the local side closes the connection
+		_ = pw.CloseWithError(encodeLines(pw, conn))
+		cancel()
+	}()
+
+	var stdout = &lineDecoder{w: conn}
+	var err = run(ctx, pr, stdout)
+	_ = pr.Close()
+
+	if err != nil && ctx.Err() == nil {
+		return errwrap.Wrapf("relay failed: {{err}}", err)
+	}
+
+	if stdout.err != nil {
+		verbose.Debug("port forwarding connection closed:", stdout.err)
+	}
+
+	return nil
+}
+
+func encodeLines(w io.Writer, r io.Reader) error {
+	var buf = make([]byte, chunkSize)
+
+	for {
+		n, err := r.Read(buf)
+
+		if n > 0 {
+			var line = base64.StdEncoding.EncodeToString(buf[:n]) + "\n"
+
+			if _, werr := io.WriteString(w, line); werr != nil {
+				return werr
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// lineDecoder writes the decoded content of each complete base64 encoded line
+type lineDecoder struct {
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+func (d *lineDecoder) Write(b []byte) (int, error) {
+	d.buf = append(d.buf, b...)
+
+	for d.err == nil {
+		var i = bytes.IndexByte(d.buf, '\n')
+
+		if i == -1 {
+			break
+		}
+
+		var line = bytes.TrimSpace(d.buf[:i])
+		d.buf = d.buf[i+1:]
+
+		var chunk, err = base64.StdEncoding.DecodeString(string(line))
+
+		if err != nil {
+			d.err = errwrap.Wrapf("invalid data from relay: {{err}}", err)
+			break
+		}
+
+		_, d.err = d.w.Write(chunk)
+	}
+
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	return len(b), nil
+}
@@ -0,0 +1,155 @@
+package portforward
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParsePorts(t *testing.T) {
+	var cases = map[string]Ports{
+		"5432":       {Local: 5432, Remote: 5432},
+		"15432:5432": {Local: 15432, Remote: 5432},
+	}
+
+	for s, want := range cases {
+		if got, err := ParsePorts(s); got != want || err != nil {
+			t.Errorf("Wanted %v to be parsed as %+v, got %+v (error: %v) instead", s, want, got, err)
+		}
+	}
+
+	for _, s := range []string{"", "a:1", "1:2:3", "0", "65536", "1:"} {
+		if _, err := ParsePorts(s); err == nil {
+			t.Errorf("Expected error parsing %q, got nil instead", s)
+		}
+	}
+}
+
+// echoRelay stands in for the relay running on the instance, echoing back what it receives
+func echoRelay(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	var s = bufio.NewScanner(stdin)
+	s.Buffer(make([]byte, 64*1024), 64*1024)
+
+	for s.Scan() {
+		var b, err = base64.StdEncoding.DecodeString(s.Text())
+
+		if err != nil {
+			return err
+		}
+
+		// split the output to make sure partial lines are handled
+		var out = base64.StdEncoding.EncodeToString(b) + "\n"
+		var half = len(out) / 2
+
+		if _, err := io.WriteString(stdout, out[:half]); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(stdout, out[half:]); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
+
+func TestRelay(t *testing.T) {
+	var local, remote = net.Pipe()
+	var done = make(chan error, 1)
+
+	go func() {
+		done <- Relay(context.Background(), remote, echoRelay)
+	}()
+
+	var payload = []byte{0x00, 0xff, '\n', 0xc3, 0x28, 'x'}
+
+	if err := local.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := local.Write(payload); err != nil {
+		t.Fatalf("Expected no error writing, got %v instead", err)
+	}
+
+	var got = make([]byte, len(payload))
+
+	if _, err := io.ReadFull(local, got); err != nil {
+		t.Fatalf("Expected no error reading, got %v instead", err)
+	}
+
+	if !bytes.Equal(got, payload) {
+		t.Errorf("Wanted %v, got %v instead", payload, got)
+	}
+
+	_ = local.Close()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error closing relay, got %v instead", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Expected relay to stop after the connection was closed")
+	}
+}
+
+func TestForwarderServe(t *testing.T) {
+	var l, err = net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var opened, closed = make(chan struct{}, 1), make(chan struct{}, 1)
+
+	var f = &Forwarder{
+		Run: echoRelay,
+		Status: func(conn net.Conn, err error, c bool) {
+			if c {
+				closed <- struct{}{}
+				return
+			}
+
+			opened <- struct{}{}
+		},
+	}
+
+	var served = make(chan error, 1)
+
+	go func() {
+		served <- f.Serve(ctx, l)
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	var got = make([]byte, 4)
+
+	if _, err := io.ReadFull(conn, got); err != nil || string(got) != "ping" {
+		t.Errorf("Expected to read ping, got %s (error: %v) instead", got, err)
+	}
+
+	<-opened
+	_ = conn.Close()
+	<-closed
+
+	cancel()
+
+	if err := <-served; err != nil {
+		t.Errorf("Expected no error serving, got %v instead", err)
+	}
+}
@@ -17,6 +17,7 @@ type Entry struct {
 	UsernameComment       string
 	Token                 string
 	TokenComment          string
+	Credential            string
 	Comment               string
 }
 
@@ -0,0 +1,236 @@
+// Package rolling restarts services one at a time, waiting for them to be healthy.
+package rolling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/services"
+)
+
+// PollingInterval for checking the health of a service
+var PollingInterval = 5 * time.Second
+
+// ErrUnhealthy is used when a service fails after restarting
+var ErrUnhealthy = errors.New("service is unhealthy")
+
+var healthy = map[string]bool{
+	"on":      true,
+	"healthy": true,
+	"up":      true,
+}
+
+var failed = map[string]bool{
+	"failed":    true,
+	"crashed":   true,
+	"unhealthy": true,
+}
+
+// Order services so that dependencies are restarted first.
+// Dependencies are taken from the packages, and are ignored if not in the list of services.
+// Services without dependencies between them are kept in alphabetical order.
+func Order(ids []string, pkgs []services.Package) ([]string, error) {
+	var deps = map[string][]string{}
+	var in = map[string]bool{}
+
+	for _, id := range ids {
+		in[id] = true
+	}
+
+	for _, p := range pkgs {
+		for _, d := range p.Dependencies {
+			if in[d] && d != p.ID {
+				deps[p.ID] = append(deps[p.ID], d)
+			}
+		}
+	}
+
+	var sorted = make([]string, len(ids))
+	copy(sorted, ids)
+	sort.Strings(sorted)
+
+	var o = orderer{
+		deps:    deps,
+		visited: map[string]bool{},
+		visit:   map[string]bool{},
+	}
+
+	for _, id := range sorted {
+		if err := o.walk(id, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return o.order, nil
+}
+
+type orderer struct {
+	deps    map[string][]string
+	visited map[string]bool
+	visit   map[string]bool
+	order   []string
+}
+
+func (o *orderer) walk(id string, path []string) error {
+	if o.visited[id] {
+		return nil
+	}
+
+	path = append(path, id)
+
+	if o.visit[id] {
+		return fmt.Errorf("circular dependency: %s", strings.Join(path, " -> "))
+	}
+
+	o.visit[id] = true
+
+	var deps = o.deps[id]
+	sort.Strings(deps)
+
+	for _, d := range deps {
+		if err := o.walk(d, path); err != nil {
+			return err
+		}
+	}
+
+	o.visit[id] = false
+	o.visited[id] = true
+	o.order = append(o.order, id)
+	return nil
+}
+
+// Restarter for services
+type Restarter struct {
+	Client *services.Client
+
+	// Timeout for each service to be healthy again
+	Timeout time.Duration
+
+	// Wait for services to be healthy before returning
+	Wait bool
+
+	// Status is called when a service status changes
+	Status func(serviceID, status string)
+}
+
+// Restart services one at a time, aborting on the first failure
+func (r *Restarter) Restart(ctx context.Context, projectID string, ids []string) error {
+	for i, id := range ids {
+		if err := r.RestartOne(ctx, projectID, id); err != nil {
+			if remaining := ids[i+1:]; len(remaining) != 0 {
+				return errwrap.Wrapf("aborted (not restarted: "+strings.Join(remaining, ", ")+"): {{err}}", err)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RestartOne service, waiting for it to be healthy if needed
+func (r *Restarter) RestartOne(ctx context.Context, projectID, serviceID string) error {
+	r.status(serviceID, "restarting")
+
+	if err := r.Client.Restart(ctx, projectID, serviceID); err != nil {
+		return errwrap.Wrapf("can't restart "+serviceID+": {{err}}", err)
+	}
+
+	if !r.Wait {
+		r.status(serviceID, "restarted")
+		return nil
+	}
+
+	if err := r.WaitHealthy(ctx, projectID, serviceID); err != nil {
+		r.status(serviceID, "failed")
+		return errwrap.Wrapf(serviceID+": {{err}}", err)
+	}
+
+	r.status(serviceID, "healthy")
+	return nil
+}
+
+// WaitHealthy waits until the service health is healthy and all its instances are running.
+// The first check happens only after the polling interval, to give the restart time to start.
+func (r *Restarter) WaitHealthy(ctx context.Context, projectID, serviceID string) error {
+	if r.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var ticker = time.NewTicker(PollingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timed out waiting to be healthy after %v", r.Timeout)
+			}
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		switch ok, err := r.check(ctx, projectID, serviceID); {
+		case err == ErrUnhealthy:
+			return err
+		case err != nil:
+			if ctx.Err() != nil {
+				continue
+			}
+
+			return err
+		case ok:
+			return nil
+		}
+	}
+}
+
+func (r *Restarter) check(ctx context.Context, projectID, serviceID string) (bool, error) {
+	var s, err = r.Client.Get(ctx, projectID, serviceID)
+
+	if err != nil {
+		return false, err
+	}
+
+	r.status(serviceID, "health: "+s.Health)
+
+	if failed[s.Health] {
+		return false, ErrUnhealthy
+	}
+
+	if !healthy[s.Health] {
+		return false, nil
+	}
+
+	instances, err := r.Client.Instances(ctx, projectID, serviceID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(instances) == 0 {
+		return false, nil
+	}
+
+	for _, i := range instances {
+		if i.State != "running" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *Restarter) status(serviceID, status string) {
+	if r.Status != nil {
+		r.Status(serviceID, status)
+	}
+}
@@ -0,0 +1,223 @@
+package rolling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+var (
+	wectx  config.Context
+	client *services.Client
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	wectx, err = config.Setup("mocks/.lcp")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := wectx.SetEndpoint(defaults.CloudRemote); err != nil {
+		panic(err)
+	}
+
+	client = services.New(wectx)
+
+	var defaultPollingInterval = PollingInterval
+	PollingInterval = time.Millisecond
+	ec := m.Run()
+	PollingInterval = defaultPollingInterval
+	os.Exit(ec)
+}
+
+func TestOrder(t *testing.T) {
+	var pkgs = []services.Package{
+		{ID: "web", Dependencies: []string{"api", "cdn"}},
+		{ID: "api", Dependencies: []string{"db", "search"}},
+		{ID: "search", Dependencies: []string{"db"}},
+	}
+
+	var got, err = Order([]string{"web", "worker", "search", "db", "api"}, pkgs)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var want = []string{"db", "search", "api", "web", "worker"}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted order %v, got %v instead", want, got)
+	}
+}
+
+func TestOrderCircularDependency(t *testing.T) {
+	var pkgs = []services.Package{
+		{ID: "a", Dependencies: []string{"b"}},
+		{ID: "b", Dependencies: []string{"c"}},
+		{ID: "c", Dependencies: []string{"a"}},
+	}
+
+	var _, err = Order([]string{"a", "b", "c"}, pkgs)
+	var want = "circular dependency: a -> b -> c -> a"
+
+	if err == nil || err.Error() != want {
+		t.Errorf("Wanted error %v, got %v instead", want, err)
+	}
+}
+
+type fakeService struct {
+	m        sync.Mutex
+	health   []string
+	restarts int
+}
+
+func (f *fakeService) restart(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	f.restarts++
+	f.m.Unlock()
+	_, _ = fmt.Fprintf(w, `"on"`)
+}
+
+func (f *fakeService) get(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	var h = f.health[0]
+
+	if len(f.health) > 1 {
+		f.health = f.health[1:]
+	}
+
+	f.m.Unlock()
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_, _ = fmt.Fprintf(w, `{"serviceId": "x", "health": "%s"}`, h)
+}
+
+func (f *fakeService) register(project, service string) {
+	var prefix = "/projects/" + project + "/services/" + service
+	servertest.Mux.HandleFunc(prefix+"/restart", f.restart)
+	servertest.Mux.HandleFunc(prefix, f.get)
+	servertest.Mux.HandleFunc(prefix+"/instances",
+		tdata.ServerJSONFileHandler("mocks/instances.json"))
+}
+
+func TestRestart(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var db = &fakeService{health: []string{"deploying", "on"}}
+	var web = &fakeService{health: []string{"on"}}
+
+	db.register("acme", "db")
+	web.register("acme", "web")
+
+	var statuses []string
+
+	var r = Restarter{
+		Client:  client,
+		Timeout: time.Second,
+		Wait:    true,
+		Status: func(serviceID, status string) {
+			statuses = append(statuses, serviceID+" "+status)
+		},
+	}
+
+	if err := r.Restart(context.Background(), "acme", []string{"db", "web"}); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if db.restarts != 1 || web.restarts != 1 {
+		t.Errorf("Expected services to be restarted once, got %d and %d instead", db.restarts, web.restarts)
+	}
+
+	var want = []string{
+		"db restarting",
+		"db health: deploying",
+		"db health: on",
+		"db healthy",
+		"web restarting",
+		"web health: on",
+		"web healthy",
+	}
+
+	if !reflect.DeepEqual(want, statuses) {
+		t.Errorf("Wanted statuses %v, got %v instead", want, statuses)
+	}
+}
+
+func TestRestartAbortOnFailure(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var db = &fakeService{health: []string{"deploying", "failed"}}
+	var web = &fakeService{health: []string{"on"}}
+
+	db.register("acme", "db")
+	web.register("acme", "web")
+
+	var r = Restarter{
+		Client:  client,
+		Timeout: time.Second,
+		Wait:    true,
+	}
+
+	var err = r.Restart(context.Background(), "acme", []string{"db", "web"})
+	var want = "aborted (not restarted: web): db: service is unhealthy"
+
+	if err == nil || err.Error() != want {
+		t.Errorf("Wanted error %v, got %v instead", want, err)
+	}
+
+	if web.restarts != 0 {
+		t.Errorf("Expected web not to be restarted")
+	}
+}
+
+func TestRestartTimeout(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var db = &fakeService{health: []string{"deploying"}}
+	db.register("acme", "db")
+
+	var r = Restarter{
+		Client:  client,
+		Timeout: 20 * time.Millisecond,
+		Wait:    true,
+	}
+
+	var err = r.Restart(context.Background(), "acme", []string{"db"})
+	var want = "db: timed out waiting to be healthy after 20ms"
+
+	if err == nil || err.Error() != want {
+		t.Errorf("Wanted error %v, got %v instead", want, err)
+	}
+}
+
+func TestRestartNoWait(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var db = &fakeService{health: []string{"failed"}}
+	db.register("acme", "db")
+
+	var r = Restarter{
+		Client: client,
+	}
+
+	if err := r.Restart(context.Background(), "acme", []string{"db"}); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+}
@@ -0,0 +1,182 @@
+// Package scaling changes the scale and resources of multiple services at once.
+package scaling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/services"
+)
+
+// DefaultConcurrency is the default number of services changed at the same time
+const DefaultConcurrency = 5
+
+// Change of the scale and resources of a service.
+// Zero values are left unchanged.
+type Change struct {
+	ServiceID string
+	Scale     int
+	CPU       json.Number
+	Memory    json.Number
+}
+
+// Result of a change
+type Result struct {
+	Change
+	Before services.Service
+	After  services.Service
+	Err    error
+}
+
+// ParsePairs of service=count arguments
+func ParsePairs(args []string) ([]Change, error) {
+	var changes = []Change{}
+	var seen = map[string]bool{}
+
+	for _, arg := range args {
+		var kv = strings.SplitN(arg, "=", 2)
+
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf(`invalid argument "%s": use service=count`, arg)
+		}
+
+		var n, err = ParseScale(kv[1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[kv[0]] {
+			return nil, fmt.Errorf(`service "%s" is repeated`, kv[0])
+		}
+
+		seen[kv[0]] = true
+		changes = append(changes, Change{
+			ServiceID: kv[0],
+			Scale:     n,
+		})
+	}
+
+	return changes, nil
+}
+
+// ParseScale value (number of instances)
+func ParseScale(s string) (int, error) {
+	var n, err = strconv.Atoi(s)
+
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf(`"%v" isn't a valid number for instances`, s)
+	}
+
+	return n, nil
+}
+
+// Scaler applies changes to services of a project
+type Scaler struct {
+	Client      *services.Client
+	Concurrency int
+}
+
+// Apply changes in parallel. Results are returned in the same order as the changes.
+func (s *Scaler) Apply(ctx context.Context, projectID string, changes []Change) ([]Result, error) {
+	var list, err = s.Client.List(ctx, projectID)
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't get services: {{err}}", err)
+	}
+
+	var results = make([]Result, len(changes))
+
+	for i, c := range changes {
+		results[i].Change = c
+
+		if results[i].Before, err = list.Get(c.ServiceID); err != nil {
+			results[i].Err = fmt.Errorf(`service "%s" not found`, c.ServiceID)
+		}
+	}
+
+	var concurrency = s.Concurrency
+
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var wg sync.WaitGroup
+	var sem = make(chan struct{}, concurrency)
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(r *Result) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			r.Err = s.apply(ctx, projectID, r)
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+func (s *Scaler) apply(ctx context.Context, projectID string, r *Result) error {
+	r.After = r.Before
+
+	if r.Scale != 0 && r.Scale != r.Before.Scale {
+		if err := s.Client.Scale(ctx, projectID, r.ServiceID, services.Scale{
+			Current: r.Scale,
+		}); err != nil {
+			return err
+		}
+
+		r.After.Scale = r.Scale
+	}
+
+	if r.CPU == "" && r.Memory == "" {
+		return nil
+	}
+
+	if err := s.Client.SetResources(ctx, projectID, r.ServiceID, services.Resources{
+		CPU:    r.CPU,
+		Memory: r.Memory,
+	}); err != nil {
+		return err
+	}
+
+	if r.CPU != "" {
+		r.After.CPU = r.CPU
+	}
+
+	if r.Memory != "" {
+		r.After.Memory = r.Memory
+	}
+
+	return nil
+}
+
+// Failed returns the number of results with errors
+func Failed(results []Result) (n int) {
+	for _, r := range results {
+		if r.Err != nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ErrNoChanges is used when there is nothing to change
+var ErrNoChanges = errors.New("no changes: use service=count pairs, a number of instances, --cpu, or --memory")
@@ -0,0 +1,213 @@
+package scaling
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/config"
+	"github.com/henvic/wedeploycli/defaults"
+	"github.com/henvic/wedeploycli/formatter"
+	"github.com/henvic/wedeploycli/servertest"
+	"github.com/henvic/wedeploycli/services"
+	"github.com/henvic/wedeploycli/tdata"
+)
+
+var (
+	wectx  config.Context
+	client *services.Client
+	update bool
+)
+
+func init() {
+	flag.BoolVar(&update, "update", false, "update golden files")
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	var err error
+	wectx, err = config.Setup("mocks/.lcp")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := wectx.SetEndpoint(defaults.CloudRemote); err != nil {
+		panic(err)
+	}
+
+	client = services.New(wectx)
+	os.Exit(m.Run())
+}
+
+func TestParsePairs(t *testing.T) {
+	var got, err = ParsePairs([]string{"web=3", "worker=1"})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	var want = []Change{
+		{ServiceID: "web", Scale: 3},
+		{ServiceID: "worker", Scale: 1},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+}
+
+func TestParsePairsFailure(t *testing.T) {
+	var cases = map[string][]string{
+		`invalid argument "web": use service=count`: {"web"},
+		`invalid argument "=3": use service=count`:  {"=3"},
+		`"x" isn't a valid number for instances`:    {"web=x"},
+		`"0" isn't a valid number for instances`:    {"web=0"},
+		`service "web" is repeated`:                 {"web=1", "web=2"},
+	}
+
+	for want, args := range cases {
+		if _, err := ParsePairs(args); err == nil || err.Error() != want {
+			t.Errorf("Wanted error %v for %v, got %v instead", want, args, err)
+		}
+	}
+}
+
+type requests struct {
+	m    sync.Mutex
+	list map[string]string
+}
+
+func (r *requests) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]interface{}
+
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("Expected no error decoding body, got %v instead", err)
+		}
+
+		var b, _ = json.Marshal(body)
+
+		r.m.Lock()
+		r.list[req.Method+" "+req.URL.Path] = string(b)
+		r.m.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestApply(t *testing.T) {
+	servertest.Setup()
+	defer servertest.Teardown()
+
+	var r = &requests{
+		list: map[string]string{},
+	}
+
+	servertest.Mux.HandleFunc("/projects/acme/services",
+		tdata.ServerJSONFileHandler("mocks/services_response.json"))
+	servertest.Mux.HandleFunc("/projects/acme/services/web/scale", r.handler(t))
+	servertest.Mux.HandleFunc("/projects/acme/services/worker", r.handler(t))
+	servertest.Mux.HandleFunc("/projects/acme/services/db/scale",
+		func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+	var s = Scaler{
+		Client: client,
+	}
+
+	var results, err = s.Apply(context.Background(), "acme", []Change{
+		{ServiceID: "web", Scale: 1},
+		{ServiceID: "worker", Scale: 2, Memory: json.Number("1024")},
+		{ServiceID: "db", Scale: 2},
+		{ServiceID: "missing", Scale: 2},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var want = map[string]string{
+		"PATCH /projects/acme/services/web/scale": `{"value":1}`,
+		"PATCH /projects/acme/services/worker":    `{"memory":1024}`,
+	}
+
+	if !reflect.DeepEqual(want, r.list) {
+		t.Errorf("Wanted requests %v, got %v instead", want, r.list)
+	}
+
+	if results[0].Err != nil || results[0].Before.Scale != 3 || results[0].After.Scale != 1 {
+		t.Errorf("Unexpected result for web: %+v", results[0])
+	}
+
+	if results[1].Err != nil || results[1].After.Memory != "1024" || results[1].After.Scale != 2 {
+		t.Errorf("Unexpected result for worker: %+v", results[1])
+	}
+
+	if results[2].Err == nil {
+		t.Errorf("Expected error for db")
+	}
+
+	if results[3].Err == nil || results[3].Err.Error() != `service "missing" not found` {
+		t.Errorf("Expected service not found error, got %v instead", results[3].Err)
+	}
+
+	if n := Failed(results); n != 2 {
+		t.Errorf("Expected 2 failures, got %d instead", n)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	var defaultNoColor = color.NoColor
+	var defaultHuman = formatter.Human
+	color.NoColor = true
+	formatter.Human = true
+
+	defer func() {
+		color.NoColor = defaultNoColor
+		formatter.Human = defaultHuman
+	}()
+
+	var results = []Result{
+		{
+			Change: Change{ServiceID: "web", Scale: 1},
+			Before: services.Service{Scale: 3, CPU: "1", Memory: "512"},
+			After:  services.Service{Scale: 1, CPU: "1", Memory: "512"},
+		},
+		{
+			Change: Change{ServiceID: "worker", CPU: "4"},
+			Before: services.Service{Scale: 2, CPU: "2", Memory: "2048"},
+			After:  services.Service{Scale: 2, CPU: "4", Memory: "2048"},
+		},
+		{
+			Change: Change{ServiceID: "missing", Scale: 2},
+			Err:    errNotFound,
+		},
+	}
+
+	var b bytes.Buffer
+
+	if err := Summary(&b, results); err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if update {
+		tdata.ToFile("mocks/summary", b.String())
+	}
+
+	if want := tdata.FromFile("mocks/summary"); b.String() != want {
+		t.Errorf("Wanted %v, got %v instead", want, b.String())
+	}
+}
+
+var errNotFound = errors.New(`service "missing" not found`)
@@ -0,0 +1,66 @@
+package scaling
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/errorhandler"
+	"github.com/henvic/wedeploycli/formatter"
+)
+
+// Summary table of before and after values
+func Summary(w io.Writer, results []Result) error {
+	var tw = formatter.NewTabWriter(w)
+
+	_, _ = fmt.Fprintln(tw, color.Format(color.FgHiBlack, "Service\tScale\tCPU\tMemory\tStatus"))
+
+	for _, r := range results {
+		var after = r.After
+
+		if r.Err != nil {
+			after = r.Before
+		}
+
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.ServiceID,
+			beforeAfter(scale(r.Before.Scale), scale(after.Scale)),
+			beforeAfter(r.Before.CPU.String(), after.CPU.String()),
+			beforeAfter(r.Before.Memory.String(), after.Memory.String()),
+			status(r))
+	}
+
+	return tw.Flush()
+}
+
+func scale(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return fmt.Sprint(n)
+}
+
+func beforeAfter(before, after string) string {
+	if before == "" {
+		before = "-"
+	}
+
+	if after == "" {
+		after = "-"
+	}
+
+	if before == after {
+		return before
+	}
+
+	return before + " → " + after
+}
+
+func status(r Result) string {
+	if r.Err != nil {
+		return color.Format(color.FgRed, "failed: %v", errorhandler.Handle(r.Err))
+	}
+
+	return color.Format(color.FgGreen, "ok")
+}
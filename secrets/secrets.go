@@ -0,0 +1,309 @@
+// Package secrets encrypts environment variables to be versioned alongside LCP.json.
+//
+// Each value is encrypted separately with AES-256-GCM using a key derived from a
+// passphrase with scrypt, keeping the variable names in clear text so changes
+// are still reviewable. The name of the variable is used as additional
+// authenticated data to prevent values from being swapped around.
+// A check value is encrypted along with the variables, so that a wrong
+// passphrase is rejected even when there are no variables.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/envfile"
+	"github.com/henvic/wedeploycli/services"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Filename of the encrypted secrets file
+const Filename = "LCP.secrets.json"
+
+// Version of the file format
+const Version = 1
+
+const (
+	keyLength = 32
+	saltSize  = 16
+
+	// checkName can't be used by environment variables
+	checkName  = "="
+	checkValue = "lcp"
+)
+
+// Bounds for the scrypt parameters read from files, to avoid weak keys
+// or having to allocate too much memory to derive a key
+var (
+	minN = 1 << 14
+	maxN = 1 << 18
+)
+
+const (
+	minR = 8
+	maxR = 16
+	maxP = 4
+)
+
+// ErrWrongPassphrase is used when values can't be decrypted with a passphrase
+var ErrWrongPassphrase = errors.New("can't decrypt secrets: wrong passphrase or corrupted file")
+
+// KDF parameters for deriving the encryption key
+type KDF struct {
+	Name string `json:"name"`
+	Salt string `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// File of encrypted secrets
+type File struct {
+	Version int               `json:"version"`
+	KDF     KDF               `json:"kdf"`
+	Check   string            `json:"check"`
+	Env     map[string]string `json:"env"`
+}
+
+// DefaultKDF parameters (see scrypt recommendations for interactive logins)
+var DefaultKDF = KDF{
+	Name: "scrypt",
+	N:    1 << 15,
+	R:    8,
+	P:    1,
+}
+
+// Read encrypted secrets file
+func Read(path string) (*File, error) {
+	var b, err = ioutil.ReadFile(path) // #nosec
+
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, errwrap.Wrapf("can't parse secrets file: {{err}}", err)
+	}
+
+	if f.Version != Version {
+		return nil, fmt.Errorf("unsupported secrets file version %d", f.Version)
+	}
+
+	if err := f.KDF.validate(); err != nil {
+		return nil, err
+	}
+
+	if f.Check == "" {
+		return nil, errors.New("invalid secrets file: missing passphrase check value")
+	}
+
+	return &f, nil
+}
+
+func (k KDF) validate() error {
+	if k.Name != DefaultKDF.Name {
+		return fmt.Errorf(`unsupported key derivation function "%s"`, k.Name)
+	}
+
+	if k.N < minN || k.N > maxN || k.N&(k.N-1) != 0 ||
+		k.R < minR || k.R > maxR ||
+		k.P < 1 || k.P > maxP {
+		return fmt.Errorf("invalid key derivation parameters (N=%d, r=%d, p=%d)", k.N, k.R, k.P)
+	}
+
+	return nil
+}
+
+func (k KDF) sameParameters(other KDF) bool {
+	return k.Name == other.Name && k.N == other.N && k.R == other.R && k.P == other.P
+}
+
+// Write encrypted secrets file
+func (f *File) Write(path string) error {
+	var b, err = json.MarshalIndent(f, "", "    ")
+
+	if err != nil {
+		return err
+	}
+
+	b = append(b, '\n')
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// Encrypt environment variables with a passphrase, always using the DefaultKDF parameters.
+// If a previous file using the same parameters is given, its salt and the encrypted values
+// of unchanged variables are kept, so that only modified variables show up on diffs.
+func Encrypt(envs []services.EnvironmentVariable, passphrase []byte, previous *File) (*File, error) {
+	var f = &File{
+		Version: Version,
+		KDF:     DefaultKDF,
+		Env:     map[string]string{},
+	}
+
+	var current map[string]string
+	var keep = previous != nil && previous.KDF.sameParameters(DefaultKDF)
+
+	if previous != nil {
+		var err error
+
+		if current, err = previous.decryptMap(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	if keep {
+		f.KDF.Salt = previous.KDF.Salt
+		f.Check = previous.Check
+	} else {
+		var salt = make([]byte, saltSize)
+
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, errwrap.Wrapf("can't generate salt: {{err}}", err)
+		}
+
+		f.KDF.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+
+	var aead, err = f.cipher(passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !keep {
+		if f.Check, err = seal(aead, checkName, checkValue); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range envs {
+		if v, ok := current[e.Name]; keep && ok && v == e.Value {
+			f.Env[e.Name] = previous.Env[e.Name]
+			continue
+		}
+
+		if f.Env[e.Name], err = seal(aead, e.Name, e.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// Decrypt environment variables with a passphrase
+func (f *File) Decrypt(passphrase []byte) ([]services.EnvironmentVariable, error) {
+	var m, err = f.decryptMap(passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var envs = []services.EnvironmentVariable{}
+
+	for k, v := range m {
+		envs = append(envs, services.EnvironmentVariable{
+			Name:  k,
+			Value: v,
+		})
+	}
+
+	envfile.Sort(envs)
+	return envs, nil
+}
+
+func (f *File) decryptMap(passphrase []byte) (map[string]string, error) {
+	var aead, err = f.cipher(passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if check, err := open(aead, checkName, f.Check); err != nil || check != checkValue {
+		return nil, ErrWrongPassphrase
+	}
+
+	var m = map[string]string{}
+
+	for k, v := range f.Env {
+		if m[k], err = open(aead, k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (f *File) cipher(passphrase []byte) (cipher.AEAD, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	if err := f.KDF.validate(); err != nil {
+		return nil, err
+	}
+
+	var salt, err = base64.StdEncoding.DecodeString(f.KDF.Salt)
+
+	if err != nil || len(salt) == 0 {
+		return nil, errors.New("invalid salt on secrets file")
+	}
+
+	key, err := scrypt.Key(passphrase, salt, f.KDF.N, f.KDF.R, f.KDF.P, keyLength)
+
+	if err != nil {
+		return nil, errwrap.Wrapf("can't derive key: {{err}}", err)
+	}
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, name, value string) (string, error) {
+	var nonce = make([]byte, aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errwrap.Wrapf("can't generate nonce: {{err}}", err)
+	}
+
+	var b = aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func open(aead cipher.AEAD, name, value string) (string, error) {
+	var b, err = base64.StdEncoding.DecodeString(value)
+
+	if err != nil || len(b) < aead.NonceSize() {
+		return "", ErrWrongPassphrase
+	}
+
+	var ns = aead.NonceSize()
+	plain, err := aead.Open(nil, b[:ns], b[ns:], []byte(name))
+
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+
+	return string(plain), nil
+}
+
+// Exists checks if a secrets file exists on a given path
+func Exists(path string) bool {
+	var _, err = os.Stat(path)
+	return err == nil
+}
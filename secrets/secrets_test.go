@@ -0,0 +1,273 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/henvic/wedeploycli/services"
+)
+
+func TestMain(m *testing.M) {
+	// cheaper key derivation for testing
+	var defaultKDF, defaultMinN = DefaultKDF, minN
+	DefaultKDF.N = 1 << 10
+	minN = 1 << 10
+	ec := m.Run()
+	DefaultKDF, minN = defaultKDF, defaultMinN
+	os.Exit(ec)
+}
+
+var envs = []services.EnvironmentVariable{
+	{Name: "API_TOKEN", Value: "abc"},
+	{Name: "DB_PASSWORD", Value: "p@ss w#rd"},
+	{Name: "EMPTY", Value: ""},
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	var f, err = Encrypt(envs, []byte("passphrase"), nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if f.Env["DB_PASSWORD"] == "p@ss w#rd" {
+		t.Errorf("Expected value to be encrypted")
+	}
+
+	got, err := f.Decrypt([]byte("passphrase"))
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v instead", err)
+	}
+
+	if !reflect.DeepEqual(envs, got) {
+		t.Errorf("Wanted %+v, got %+v instead", envs, got)
+	}
+}
+
+func TestDecryptWrongPassphrase(t *testing.T) {
+	var f, err = Encrypt(envs, []byte("passphrase"), nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if _, err := f.Decrypt([]byte("wrong")); err != ErrWrongPassphrase {
+		t.Errorf("Expected error to be %v, got %v instead", ErrWrongPassphrase, err)
+	}
+}
+
+func TestDecryptSwappedValues(t *testing.T) {
+	var f, err = Encrypt(envs, []byte("passphrase"), nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	f.Env["API_TOKEN"], f.Env["DB_PASSWORD"] = f.Env["DB_PASSWORD"], f.Env["API_TOKEN"]
+
+	if _, err := f.Decrypt([]byte("passphrase")); err != ErrWrongPassphrase {
+		t.Errorf("Expected error to be %v, got %v instead", ErrWrongPassphrase, err)
+	}
+}
+
+func TestEncryptKeepsUnchangedValues(t *testing.T) {
+	var passphrase = []byte("passphrase")
+	var previous, err = Encrypt(envs, passphrase, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var next = []services.EnvironmentVariable{
+		{Name: "API_TOKEN", Value: "abc"},
+		{Name: "DB_PASSWORD", Value: "changed"},
+	}
+
+	f, err := Encrypt(next, passphrase, previous)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if f.KDF != previous.KDF {
+		t.Errorf("Expected key derivation parameters to be kept")
+	}
+
+	if f.Env["API_TOKEN"] != previous.Env["API_TOKEN"] {
+		t.Errorf("Expected unchanged value to keep its encrypted value")
+	}
+
+	if f.Env["DB_PASSWORD"] == previous.Env["DB_PASSWORD"] {
+		t.Errorf("Expected changed value to be encrypted again")
+	}
+
+	if _, ok := f.Env["EMPTY"]; ok {
+		t.Errorf("Expected removed variable to be removed")
+	}
+
+	if _, err := Encrypt(next, []byte("wrong"), previous); err != ErrWrongPassphrase {
+		t.Errorf("Expected error to be %v, got %v instead", ErrWrongPassphrase, err)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "lcp-secrets-")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	var path = filepath.Join(dir, Filename)
+
+	f, err := Encrypt(envs, []byte("passphrase"), nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if err := f.Write(path); err != nil {
+		t.Fatalf("Expected no error writing file, got %v instead", err)
+	}
+
+	if !Exists(path) {
+		t.Errorf("Expected file to exist")
+	}
+
+	got, err := Read(path)
+
+	if err != nil {
+		t.Fatalf("Expected no error reading file, got %v instead", err)
+	}
+
+	if !reflect.DeepEqual(f, got) {
+		t.Errorf("Wanted %+v, got %+v instead", f, got)
+	}
+}
+
+func TestReadMock(t *testing.T) {
+	var f, err = Read("mocks/LCP.secrets.json")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	got, err := f.Decrypt([]byte("correct horse battery staple"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var want = []services.EnvironmentVariable{
+		{Name: "DB_PASSWORD", Value: "hunter2"},
+		{Name: "DB_USER", Value: "admin"},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	if _, err := Read("mocks/unsupported.json"); err == nil || err.Error() != "unsupported secrets file version 2" {
+		t.Errorf("Expected unsupported version error, got %v instead", err)
+	}
+}
+
+func TestDecryptEmptyWrongPassphrase(t *testing.T) {
+	var f, err = Encrypt(nil, []byte("passphrase"), nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if _, err := f.Decrypt([]byte("wrong")); err != ErrWrongPassphrase {
+		t.Errorf("Expected error to be %v, got %v instead", ErrWrongPassphrase, err)
+	}
+
+	if _, err := Encrypt(envs, []byte("wrong"), f); err != ErrWrongPassphrase {
+		t.Errorf("Expected error to be %v, got %v instead", ErrWrongPassphrase, err)
+	}
+}
+
+func TestEncryptUpgradesKDF(t *testing.T) {
+	var passphrase = []byte("passphrase")
+	var previous, err = Encrypt(envs, passphrase, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	var defaultKDF = DefaultKDF
+	DefaultKDF.N = 1 << 11
+
+	defer func() {
+		DefaultKDF = defaultKDF
+	}()
+
+	f, err := Encrypt(envs, passphrase, previous)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v instead", err)
+	}
+
+	if f.KDF.N != 1<<11 || f.KDF.Salt == previous.KDF.Salt {
+		t.Errorf("Expected file to be encrypted again with the default parameters, got %+v instead", f.KDF)
+	}
+
+	if f.Check == previous.Check || f.Env["API_TOKEN"] == previous.Env["API_TOKEN"] {
+		t.Errorf("Expected all values to be encrypted again")
+	}
+
+	got, err := f.Decrypt(passphrase)
+
+	if err != nil || !reflect.DeepEqual(envs, got) {
+		t.Errorf("Wanted %+v, got %+v (error: %v) instead", envs, got, err)
+	}
+}
+
+func TestInvalidKDF(t *testing.T) {
+	var cases = []KDF{
+		{Name: "pbkdf2", N: 1 << 15, R: 8, P: 1},
+		{Name: "scrypt", N: 2, R: 8, P: 1},
+		{Name: "scrypt", N: 1 << 30, R: 8, P: 1},
+		{Name: "scrypt", N: 1<<15 + 1, R: 8, P: 1},
+		{Name: "scrypt", N: 1 << 15, R: 1, P: 1},
+		{Name: "scrypt", N: 1 << 15, R: 1 << 20, P: 1},
+		{Name: "scrypt", N: 1 << 15, R: 8, P: 0},
+		{Name: "scrypt", N: 1 << 15, R: 8, P: 1 << 20},
+	}
+
+	for _, k := range cases {
+		var f, err = Encrypt(envs, []byte("passphrase"), nil)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v instead", err)
+		}
+
+		k.Salt = f.KDF.Salt
+		f.KDF = k
+
+		if _, err := f.Decrypt([]byte("passphrase")); err == nil {
+			t.Errorf("Expected error for parameters %+v, got nil instead", k)
+		}
+
+		if _, err := Encrypt(envs, []byte("passphrase"), f); err == nil {
+			t.Errorf("Expected error encrypting with previous parameters %+v, got nil instead", k)
+		}
+	}
+}
+
+func TestReadMissingCheck(t *testing.T) {
+	if _, err := Read("mocks/missing-check.json"); err == nil ||
+		err.Error() != "invalid secrets file: missing passphrase check value" {
+		t.Errorf("Expected missing check value error, got %v instead", err)
+	}
+}
@@ -0,0 +1,110 @@
+package servertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// DeviceAuthServer is a stand-in authorization server for the device login flow
+type DeviceAuthServer struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURI string
+	Token           string
+	ExpiresIn       int
+	Interval        int
+
+	// SlowDown asks the client to poll slower this many times
+	SlowDown int
+
+	m      sync.Mutex
+	status string
+	polls  int
+}
+
+// Device authorization status
+const (
+	DevicePending  = "authorization_pending"
+	DeviceApproved = "approved"
+	DeviceDenied   = "access_denied"
+	DeviceExpired  = "expired_token"
+)
+
+// Register the device login handlers on a mux
+func (d *DeviceAuthServer) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/login/device", d.authorizeHandler)
+	mux.HandleFunc("/login/device/token", d.tokenHandler)
+}
+
+// SetStatus of the authorization (say, DeviceApproved after the user approves it)
+func (d *DeviceAuthServer) SetStatus(status string) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.status = status
+}
+
+// Polls received for the token
+func (d *DeviceAuthServer) Polls() int {
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.polls
+}
+
+func (d *DeviceAuthServer) authorizeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		deviceError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+
+	d.SetStatus(DevicePending)
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"device_code":               d.DeviceCode,
+		"user_code":                 d.UserCode,
+		"verification_uri":          d.VerificationURI,
+		"verification_uri_complete": d.VerificationURI + "?user_code=" + d.UserCode,
+		"expires_in":                d.ExpiresIn,
+		"interval":                  d.Interval,
+	})
+}
+
+func (d *DeviceAuthServer) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.polls++
+
+	if r.Method != http.MethodPost || r.FormValue("device_code") != d.DeviceCode {
+		deviceError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	if d.SlowDown > 0 {
+		d.SlowDown--
+		deviceError(w, http.StatusBadRequest, "slow_down")
+		return
+	}
+
+	if d.status != DeviceApproved {
+		deviceError(w, http.StatusBadRequest, d.status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_, _ = fmt.Fprintf(w, `{"token": "%s"}`, d.Token)
+}
+
+func deviceError(w http.ResponseWriter, status int, reason string) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  status,
+		"message": http.StatusText(status),
+		"errors": []map[string]string{
+			{"reason": reason},
+		},
+	})
+}
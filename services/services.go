@@ -119,6 +119,8 @@ type Package struct {
 	ProjectID     string            `json:"projectId,omitempty"`
 	Scale         int               `json:"scale,omitempty"`
 	Image         string            `json:"image,omitempty"`
+	CPU           json.Number       `json:"cpu,omitempty"`
+	Memory        json.Number       `json:"memory,omitempty"`
 	CustomDomains []string          `json:"customDomains,omitempty"`
 	Env           map[string]string `json:"env,omitempty"`
 	Dependencies  []string          `json:"dependencies,omitempty"`
@@ -132,6 +134,8 @@ func (p Package) Service() *Service {
 		ServiceID:     p.ID,
 		Scale:         p.Scale,
 		Image:         p.Image,
+		CPU:           p.CPU,
+		Memory:        p.Memory,
 		CustomDomains: p.CustomDomains,
 	}
 }
@@ -606,6 +610,30 @@ func (c *Client) Scale(ctx context.Context, projectID, serviceID string, s Scale
 	return apihelper.Validate(req, req.Patch())
 }
 
+// Resources of the service
+type Resources struct {
+	CPU    json.Number `json:"cpu,omitempty"`
+	Memory json.Number `json:"memory,omitempty"`
+}
+
+// SetResources sets the cpu and memory for a given service
+func (c *Client) SetResources(ctx context.Context, projectID, serviceID string, r Resources) (err error) {
+	var req = c.Client.URL(ctx,
+		"/projects",
+		url.PathEscape(projectID),
+		"/services",
+		url.PathEscape(serviceID))
+	c.Client.Auth(req)
+
+	err = apihelper.SetBody(req, &r)
+
+	if err != nil {
+		return err
+	}
+
+	return apihelper.Validate(req, req.Patch())
+}
+
 // Restart restarts a service inside a project
 func (c *Client) Restart(ctx context.Context, projectID, serviceID string) error {
 	var req = c.Client.URL(ctx, "/projects/"+
@@ -680,6 +680,48 @@ func TestScale(t *testing.T) {
 	servertest.Teardown()
 }
 
+func TestSetResources(t *testing.T) {
+	servertest.Setup()
+
+	servertest.Mux.HandleFunc("/projects/foo/services/bar",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPatch {
+				t.Errorf("Expected method %v, got %v instead", http.MethodPatch, r.Method)
+			}
+
+			var body, err = ioutil.ReadAll(r.Body)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			var data map[string]json.RawMessage
+
+			err = json.Unmarshal(body, &data)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			jsonlib.AssertJSONMarshal(t,
+				`{"cpu": 2, "memory": 2048}`,
+				data)
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+	var r = Resources{
+		CPU:    json.Number("2"),
+		Memory: json.Number("2048"),
+	}
+
+	if err := client.SetResources(context.Background(), "foo", "bar", r); err != nil {
+		t.Errorf("Unexpected error on setting service resources: %v", err)
+	}
+
+	servertest.Teardown()
+}
+
 func TestRestart(t *testing.T) {
 	servertest.Setup()
 
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/henvic/wedeploycli/verbose"
@@ -57,10 +56,12 @@ func (p *Process) PipeStdin() (err error) {
 }
 
 func (p *Process) pipeStdinGoroutine() {
-	var inStream io.ReadCloser = os.Stdin
+	var inStream = p.stdin()
 	reader := bufio.NewReader(inStream)
 	defer func() {
-		_ = inStream.Close()
+		if c, ok := inStream.(io.Closer); ok {
+			_ = c.Close()
+		}
 	}()
 
 	select {
@@ -138,14 +139,14 @@ func (p *Process) readBuf(reader *bufio.Reader, b rune) ([]byte, error) {
 // PipeStdout from UNIX socket to websocket
 func (p *Process) PipeStdout() error {
 	return p.shell.On("stdout", func(content string) {
-		fmt.Print(content)
+		_, _ = fmt.Fprint(p.stdout(), content)
 	})
 }
 
 // PipeStderr from UNIX socket to websocket
 func (p *Process) PipeStderr() error {
 	return p.shell.On("stderr", func(content string) {
-		_, _ = fmt.Fprint(os.Stderr, content)
+		_, _ = fmt.Fprint(p.stderr(), content)
 	})
 }
 
@@ -10,7 +10,7 @@ import (
 
 func (p *Process) handleConnections() {
 	if err := p.conn.On(gosocketio.OnDisconnect, func() {
-		p.err <- errors.New("disconnected from gateway")
+		p.err <- &TransportError{errors.New("disconnected from gateway")}
 		p.ctxCancel()
 	}); err != nil {
 		p.err <- err
@@ -19,7 +19,7 @@ func (p *Process) handleConnections() {
 	}
 
 	if err := p.conn.On(gosocketio.OnError, func(err error) {
-		p.err <- errwrap.Wrapf("connection error: {{err}}", err)
+		p.err <- &TransportError{errwrap.Wrapf("connection error: {{err}}", err)}
 		p.ctxCancel()
 	}); err != nil {
 		p.err <- err
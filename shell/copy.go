@@ -0,0 +1,293 @@
+package shell
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/errwrap"
+)
+
+// The exec channel transports text, so archives are base64 encoded on both directions.
+const (
+	uploadScript   = `mkdir -p "$1" && base64 -d | tar -x -f - -C "$1"`
+	downloadScript = `cd "$(dirname "$1")" && tar -c -f - "$(basename "$1")" | base64`
+)
+
+// Upload a local file or directory to a directory on the instance
+func Upload(ctx context.Context, params Params, src, dst string) error {
+	if _, err := os.Lstat(src); err != nil {
+		return err
+	}
+
+	var pr, pw = io.Pipe()
+
+	go func() {
+		var enc = base64.NewEncoder(base64.StdEncoding, pw)
+		var err = WriteTar(enc, src)
+
+		if err == nil {
+			err = enc.Close()
+		}
+
+		_ = pw.CloseWithError(err)
+	}()
+
+	params.AttachStdin = true
+	params.TTY = false
+	params.Stdin = pr
+
+	var err = Run(ctx, params, "sh", "-c", uploadScript, "sh", dst)
+	_ = pr.Close()
+	return copyResult(err)
+}
+
+// Download a file or directory from the instance to a local directory
+func Download(ctx context.Context, params Params, src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	var pr, pw = io.Pipe()
+	var extracted = make(chan error, 1)
+
+	go func() {
+		var err = ExtractTar(base64.NewDecoder(base64.StdEncoding, pr), dst)
+		_ = pr.CloseWithError(err)
+		extracted <- err
+	}()
+
+	params.AttachStdin = false
+	params.TTY = false
+	params.Stdout = pw
+
+	var err = copyResult(Run(ctx, params, "sh", "-c", downloadScript, "sh", src))
+	_ = pw.Close()
+
+	if eerr := <-extracted; err == nil && eerr != nil {
+		err = errwrap.Wrapf("can't extract files: {{err}}", eerr)
+	}
+
+	return err
+}
+
+func copyResult(err error) error {
+	ee, ok := err.(*ExitError)
+
+	if !ok {
+		return err
+	}
+
+	if code, ok := ee.GetExitCode(); ok && code == 0 {
+		return nil
+	}
+
+	return errwrap.Wrapf("copying files failed on the instance: {{err}}", err)
+}
+
+// WriteTar archive of a file or directory, keeping its base name
+func WriteTar(w io.Writer, src string) error {
+	var tw = tar.NewWriter(w)
+	var base = filepath.Dir(filepath.Clean(src))
+
+	var err = filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(base, path)
+
+		if err != nil {
+			return err
+		}
+
+		return writeTarEntry(tw, path, filepath.ToSlash(name), fi)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+func writeTarEntry(tw *tar.Writer, path, name string, fi os.FileInfo) error {
+	var link string
+
+	if fi.Mode()&os.ModeSymlink != 0 {
+		var err error
+
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+
+	var h, err = tar.FileInfoHeader(fi, link)
+
+	if err != nil {
+		return err
+	}
+
+	h.Name = name
+
+	if fi.IsDir() {
+		h.Name += "/"
+	}
+
+	if err := tw.WriteHeader(h); err != nil {
+		return err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// ExtractTar archive to a directory, refusing entries that would be written outside of it
+func ExtractTar(r io.Reader, dst string) error {
+	var tr = tar.NewReader(r)
+
+	for {
+		h, err := tr.Next()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		target, err := extractPath(dst, h.Name)
+
+		if err != nil {
+			return err
+		}
+
+		if err := extractEntry(tr, h, dst, target); err != nil {
+			return err
+		}
+	}
+}
+
+func extractPath(dst, name string) (string, error) {
+	var target = filepath.Join(dst, filepath.FromSlash(name))
+
+	if !within(dst, target) {
+		return "", fmt.Errorf(`refusing to extract "%s" outside of the destination directory`, name)
+	}
+
+	// a previously extracted symbolic link might redirect the entry elsewhere (say, "a -> ." then "a/b -> ..")
+	link, err := symlinkOnPath(dst, target)
+
+	if err != nil {
+		return "", err
+	}
+
+	if link != "" {
+		return "", fmt.Errorf(`refusing to extract "%s" through symbolic link "%s"`, name, link)
+	}
+
+	return target, nil
+}
+
+// symlinkOnPath returns the first existing component of the path below dir that is a symbolic link, if any
+func symlinkOnPath(dir, path string) (string, error) {
+	dir = filepath.Clean(dir)
+	rel, err := filepath.Rel(dir, path)
+
+	if err != nil {
+		return "", err
+	}
+
+	var components = strings.Split(rel, string(filepath.Separator))
+
+	for i := range components {
+		var c = filepath.Join(components[:i+1]...)
+
+		if c == "." {
+			continue
+		}
+
+		fi, err := os.Lstat(filepath.Join(dir, c))
+
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return filepath.ToSlash(c), nil
+		}
+	}
+
+	return "", nil
+}
+
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func extractEntry(tr *tar.Reader, h *tar.Header, dst, target string) error {
+	switch h.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, os.FileMode(h.Mode)|0700)
+	case tar.TypeReg:
+		return extractFile(tr, target, os.FileMode(h.Mode))
+	case tar.TypeSymlink:
+		if filepath.IsAbs(h.Linkname) || !within(dst, filepath.Join(filepath.Dir(target), h.Linkname)) {
+			return fmt.Errorf(`refusing to extract symbolic link "%s" pointing outside of the destination directory`, h.Name)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		return os.Symlink(h.Linkname, target)
+	case tar.TypeXGlobalHeader:
+		return nil
+	}
+
+	return fmt.Errorf(`unsupported file type for "%s"`, h.Name)
+}
+
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,205 @@
+package shell
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	var dir, err = ioutil.TempDir("", "lcp-cp")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return dir
+}
+
+func TestWriteExtractTar(t *testing.T) {
+	var src = tempDir(t)
+	var dst = tempDir(t)
+
+	var dir = filepath.Join(src, "dumps")
+	var binary = []byte{0x00, 0xff, 0xfe, 0x80, '\n', 0xc3, 0x28}
+
+	if err := os.MkdirAll(filepath.Join(dir, "threads"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "heap.hprof"), binary, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "threads", "1.txt"), []byte("thread dump"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	var enc = base64.NewEncoder(base64.StdEncoding, &b)
+
+	if err := WriteTar(enc, dir); err != nil {
+		t.Fatalf("Expected no error writing tar, got %v instead", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExtractTar(base64.NewDecoder(base64.StdEncoding, &b), dst); err != nil {
+		t.Fatalf("Expected no error extracting tar, got %v instead", err)
+	}
+
+	got, err := ioutil.ReadFile(filepath.Join(dst, "dumps", "heap.hprof"))
+
+	if err != nil || !bytes.Equal(got, binary) {
+		t.Errorf("Expected binary file to be copied unchanged, got %v (error: %v) instead", got, err)
+	}
+
+	fi, err := os.Stat(filepath.Join(dst, "dumps", "heap.hprof"))
+
+	if err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected file mode to be preserved, got %v (error: %v) instead", fi, err)
+	}
+
+	got, err = ioutil.ReadFile(filepath.Join(dst, "dumps", "threads", "1.txt"))
+
+	if err != nil || string(got) != "thread dump" {
+		t.Errorf("Expected nested file to be copied, got %s (error: %v) instead", got, err)
+	}
+}
+
+func TestExtractTarSingleFile(t *testing.T) {
+	var src = filepath.Join(tempDir(t), "hotfix.jar")
+	var dst = tempDir(t)
+
+	if err := ioutil.WriteFile(src, []byte("jar"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+
+	if err := WriteTar(&b, src); err != nil {
+		t.Fatalf("Expected no error writing tar, got %v instead", err)
+	}
+
+	if err := ExtractTar(&b, dst); err != nil {
+		t.Fatalf("Expected no error extracting tar, got %v instead", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dst, "hotfix.jar")); err != nil {
+		t.Errorf("Expected file to be extracted, got %v instead", err)
+	}
+}
+
+func TestExtractTarOutsideDestination(t *testing.T) {
+	var cases = []tar.Header{
+		{Name: "../escape", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "a/../../escape", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../etc/passwd"},
+		{Name: "abs", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+	}
+
+	for _, h := range cases {
+		var b bytes.Buffer
+		var tw = tar.NewWriter(&b)
+		var h = h
+
+		if err := tw.WriteHeader(&h); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var err = ExtractTar(&b, tempDir(t))
+
+		if err == nil || !strings.Contains(err.Error(), "refusing to extract") {
+			t.Errorf("Expected %v to be refused, got %v instead", h.Name, err)
+		}
+	}
+}
+
+func TestExtractTarChainedSymlinks(t *testing.T) {
+	var parent = tempDir(t)
+	var dst = filepath.Join(parent, "dst")
+
+	if err := os.Mkdir(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	var tw = tar.NewWriter(&b)
+
+	var headers = []tar.Header{
+		{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+		{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+		{Name: "b/evil", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+	}
+
+	for _, h := range headers {
+		var h = h
+
+		if err := tw.WriteHeader(&h); err != nil {
+			t.Fatal(err)
+		}
+
+		if h.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte("evil")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var err = ExtractTar(&b, dst)
+
+	if err == nil || !strings.Contains(err.Error(), `through symbolic link "a"`) {
+		t.Errorf("Expected chained symbolic links to be refused, got %v instead", err)
+	}
+
+	if _, err := os.Lstat(filepath.Join(parent, "evil")); !os.IsNotExist(err) {
+		t.Errorf("Expected no file to be written outside of the destination directory, got %v instead", err)
+	}
+}
+
+func TestExtractTarOverSymlink(t *testing.T) {
+	var dst = tempDir(t)
+	var outside = filepath.Join(tempDir(t), "passwd")
+
+	if err := os.Symlink(outside, filepath.Join(dst, "config")); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	var tw = tar.NewWriter(&b)
+
+	if err := tw.WriteHeader(&tar.Header{Name: "config", Typeflag: tar.TypeReg, Mode: 0644}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExtractTar(&b, dst); err == nil || !strings.Contains(err.Error(), "refusing to extract") {
+		t.Errorf("Expected writing over symbolic link to be refused, got %v instead", err)
+	}
+
+	if _, err := os.Lstat(outside); !os.IsNotExist(err) {
+		t.Errorf("Expected no file to be written through the symbolic link, got %v instead", err)
+	}
+}
@@ -0,0 +1,122 @@
+package shell
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/errwrap"
+)
+
+// JobsDirectory on the instance where detached commands write their output and exit code
+const JobsDirectory = "/tmp/lcp-jobs"
+
+// the exit code is moved into place only once written, as attaching stops when the file exists
+const detachScript = `d="$1"; j="$2"; shift 2; mkdir -p "$d" || exit
+nohup sh -c '"$@"; echo $? > "$0.exit.tmp"; mv "$0.exit.tmp" "$0.exit"' "$d/$j" "$@" > "$d/$j.log" 2>&1 < /dev/null &
+echo "$!" > "$d/$j.pid"`
+
+const attachScript = `d="$1"; j="$2"
+[ -f "$d/$j.log" ] || { echo "job $j not found on this instance" >&2; exit 127; }
+tail -c "+$3" -f "$d/$j.log" & t=$!
+while [ ! -f "$d/$j.exit" ]; do sleep 1; done
+sleep 1; kill "$t" 2>/dev/null
+exit "$(cat "$d/$j.exit")"`
+
+var jobIDRegexp = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+// NewJobID for a detached command started at a given time.
+// A random suffix avoids jobs started on the same second sharing their files.
+func NewJobID(t time.Time) (string, error) {
+	var b = make([]byte, 4)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", errwrap.Wrapf("can't generate job ID: {{err}}", err)
+	}
+
+	return t.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b), nil
+}
+
+// ValidateJobID to avoid paths outside of the jobs directory
+func ValidateJobID(job string) error {
+	if !jobIDRegexp.MatchString(job) {
+		return fmt.Errorf(`invalid job "%s"`, job)
+	}
+
+	return nil
+}
+
+// DetachCommand returns the command to start a process detached from the session under nohup
+func DetachCommand(job, cmd string, args []string) (string, []string) {
+	return "sh", append([]string{"-c", detachScript, "sh", JobsDirectory, job, cmd}, args...)
+}
+
+// AttachCommand returns the command to follow the output of a job, starting from a given byte offset,
+// and exiting with the job's exit code once it finishes
+func AttachCommand(job string, offset int64) (string, []string) {
+	return "sh", []string{"-c", attachScript, "sh", JobsDirectory, job, strconv.FormatInt(offset+1, 10)}
+}
+
+// Detach starts a command detached from the session, returning the instance it is running on
+func Detach(ctx context.Context, params Params, job, cmd string, args ...string) (instance string, err error) {
+	if err := ValidateJobID(job); err != nil {
+		return "", err
+	}
+
+	params.AttachStdin = false
+	params.TTY = false
+
+	var c, a = DetachCommand(job, cmd, args)
+	instance, err = RunInstance(ctx, params, c, a...)
+
+	if ee, ok := err.(*ExitError); ok {
+		if code, ok := ee.GetExitCode(); ok && code == 0 {
+			err = nil
+		}
+	}
+
+	return instance, err
+}
+
+// Attach to the output of a detached job, reconnecting without repeating output when the connection is lost
+func Attach(ctx context.Context, params Params, job string, r Reconnect) error {
+	if err := ValidateJobID(job); err != nil {
+		return err
+	}
+
+	var stdout = params.Stdout
+
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
+	var cw = &countingWriter{w: stdout}
+
+	params.AttachStdin = false
+	params.TTY = false
+	params.Stdout = cw
+
+	r.Update = func(p *Params, cmd *string, args *[]string) {
+		*cmd, *args = AttachCommand(job, atomic.LoadInt64(&cw.n))
+	}
+
+	return r.Run(ctx, params, "")
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
@@ -0,0 +1,106 @@
+package shell
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewJobID(t *testing.T) {
+	var now = time.Date(2019, 10, 1, 12, 30, 5, 0, time.UTC)
+	var got, err = NewJobID(now)
+
+	if err != nil || !strings.HasPrefix(got, "20191001-123005-") {
+		t.Errorf("Expected job ID to start with 20191001-123005-, got %v (error: %v) instead", got, err)
+	}
+
+	if err := ValidateJobID(got); err != nil {
+		t.Errorf("Expected job ID to be valid, got %v instead", err)
+	}
+
+	if other, _ := NewJobID(now); other == got {
+		t.Errorf("Expected job IDs started on the same second to be different, got %v twice", got)
+	}
+
+	for _, job := range []string{"", "../etc", "a/b", "-rf", "20191001-123005", "20191001-123005-../etc"} {
+		if err := ValidateJobID(job); err == nil {
+			t.Errorf("Expected job ID %q to be invalid", job)
+		}
+	}
+}
+
+// runLocally runs a detach or attach command with sh, using a given jobs directory
+func runLocally(t *testing.T, dir, cmd string, args []string) (string, int) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+
+	args[3] = dir
+
+	var c = exec.Command(cmd, args...) // #nosec
+	var b bytes.Buffer
+	c.Stdout = &b
+	c.Stderr = &b
+
+	var err = c.Run()
+
+	if ee, ok := err.(*exec.ExitError); ok {
+		return b.String(), ee.ExitCode()
+	}
+
+	if err != nil {
+		t.Fatalf("Expected no error running command, got %v instead", err)
+	}
+
+	return b.String(), 0
+}
+
+func TestDetachAttachScripts(t *testing.T) {
+	var dir = tempDir(t)
+
+	var cmd, args = DetachCommand("job1", "sh", []string{"-c", `echo "out 'quoted'"; echo err >&2; exit 4`})
+
+	if out, code := runLocally(t, dir, cmd, args); code != 0 || out != "" {
+		t.Fatalf("Expected detach to succeed silently, got %q (exit code %d) instead", out, code)
+	}
+
+	cmd, args = AttachCommand("job1", 0)
+	out, code := runLocally(t, dir, cmd, args)
+
+	if code != 4 {
+		t.Errorf("Expected attach to exit with the job exit code 4, got %d instead", code)
+	}
+
+	if want := "out 'quoted'\nerr\n"; out != want {
+		t.Errorf("Wanted output %q, got %q instead", want, out)
+	}
+
+	cmd, args = AttachCommand("job1", 4)
+
+	if out, _ := runLocally(t, dir, cmd, args); out != "'quoted'\nerr\n" {
+		t.Errorf("Expected attach to resume from offset, got %q instead", out)
+	}
+
+	if _, err := ioutil.ReadFile(filepath.Join(dir, "job1.pid")); err != nil {
+		t.Errorf("Expected pid file to exist, got %v instead", err)
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "job1.exit")); err != nil || string(b) != "4\n" {
+		t.Errorf("Expected exit file with 4, got %q (error: %v) instead", b, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "job1.exit.tmp")); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary exit file to be moved, got %v instead", err)
+	}
+
+	cmd, args = AttachCommand("missing", 0)
+
+	if out, code := runLocally(t, dir, cmd, args); code != 127 || out != "job missing not found on this instance\n" {
+		t.Errorf("Expected job not found error, got %q (exit code %d) instead", out, code)
+	}
+}
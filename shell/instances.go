@@ -0,0 +1,199 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/henvic/wedeploycli/color"
+	"github.com/henvic/wedeploycli/colorwheel"
+)
+
+// DefaultConcurrency for running a command on all instances of a service
+const DefaultConcurrency = 4
+
+// ConnectionFailureExitCode is used when the command couldn't run on an instance
+const ConnectionFailureExitCode = 255
+
+// InstanceResult of running a command on an instance
+type InstanceResult struct {
+	Instance string
+	ExitCode int
+	Err      error
+}
+
+// RunAll runs a command on the given instances, prefixing each output line with the instance ID
+func RunAll(ctx context.Context, params Params, instances []string, concurrency int,
+	cmd string, args ...string) []InstanceResult {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	var stdout = params.Stdout
+	var stderr = params.Stderr
+
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	var m sync.Mutex
+	var wheel = colorwheel.New(color.TextPalette)
+	var results = make([]InstanceResult, len(instances))
+	var sem = make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, instance := range instances {
+		var prefix = color.Format(wheel.Get(instance), "[%s]", trimInstance(instance)) + " "
+
+		var ip = params
+		var out = &PrefixWriter{Writer: stdout, Prefix: prefix, Lock: &m}
+		var errOut = &PrefixWriter{Writer: stderr, Prefix: prefix, Lock: &m}
+
+		ip.Instance = instance
+		ip.AttachStdin = false
+		ip.TTY = false
+		ip.Stdout = out
+		ip.Stderr = errOut
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var err = Run(ctx, ip, cmd, args...)
+
+			_ = out.Flush()
+			_ = errOut.Flush()
+
+			results[i] = newInstanceResult(ip.Instance, err)
+		}(i)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func newInstanceResult(instance string, err error) InstanceResult {
+	var r = InstanceResult{
+		Instance: instance,
+		Err:      err,
+	}
+
+	ee, ok := err.(*ExitError)
+
+	switch {
+	case err == nil:
+	case !ok:
+		r.ExitCode = ConnectionFailureExitCode
+	default:
+		if code, ok := ee.GetExitCode(); ok {
+			r.ExitCode = code
+		} else {
+			r.ExitCode = ConnectionFailureExitCode
+		}
+
+		if r.ExitCode == 0 {
+			r.Err = nil
+		}
+	}
+
+	return r
+}
+
+// AggregateExitCode returns the highest exit code of the results
+func AggregateExitCode(results []InstanceResult) int {
+	var code int
+
+	for _, r := range results {
+		if r.ExitCode > code {
+			code = r.ExitCode
+		}
+	}
+
+	return code
+}
+
+func trimInstance(instance string) string {
+	if len(instance) > 12 {
+		return instance[:12]
+	}
+
+	return instance
+}
+
+// PrefixWriter writes complete lines prefixed with a given value
+type PrefixWriter struct {
+	Writer io.Writer
+	Prefix string
+
+	// Lock is shared by writers of the same output to avoid interleaving lines
+	Lock sync.Locker
+
+	buf []byte
+}
+
+// Write data, holding incomplete lines until they are completed or flushed
+func (p *PrefixWriter) Write(b []byte) (int, error) {
+	p.lock()
+	defer p.unlock()
+
+	p.buf = append(p.buf, b...)
+
+	var i = bytes.LastIndexByte(p.buf, '\n')
+
+	if i == -1 {
+		return len(b), nil
+	}
+
+	var err = p.write(p.buf[:i+1])
+	p.buf = append([]byte{}, p.buf[i+1:]...)
+	return len(b), err
+}
+
+// Flush incomplete line
+func (p *PrefixWriter) Flush() error {
+	p.lock()
+	defer p.unlock()
+
+	if len(p.buf) == 0 {
+		return nil
+	}
+
+	var err = p.write(append(p.buf, '\n'))
+	p.buf = nil
+	return err
+}
+
+func (p *PrefixWriter) lock() {
+	if p.Lock != nil {
+		p.Lock.Lock()
+	}
+}
+
+func (p *PrefixWriter) unlock() {
+	if p.Lock != nil {
+		p.Lock.Unlock()
+	}
+}
+
+func (p *PrefixWriter) write(lines []byte) error {
+	var b bytes.Buffer
+
+	for _, l := range bytes.SplitAfter(lines, []byte("\n")) {
+		if len(l) != 0 {
+			b.WriteString(p.Prefix)
+			b.Write(l)
+		}
+	}
+
+	_, err := p.Writer.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,74 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/henvic/wedeploycli/shell/internal/kubernetes"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var b bytes.Buffer
+	var m sync.Mutex
+
+	var w1 = &PrefixWriter{Writer: &b, Prefix: "[a] ", Lock: &m}
+	var w2 = &PrefixWriter{Writer: &b, Prefix: "[b] ", Lock: &m}
+
+	_, _ = w1.Write([]byte("hello "))
+	_, _ = w2.Write([]byte("one\ntwo\nthr"))
+	_, _ = w1.Write([]byte("world\n"))
+	_, _ = w2.Write([]byte("ee"))
+
+	if err := w1.Flush(); err != nil {
+		t.Errorf("Expected no error flushing, got %v instead", err)
+	}
+
+	if err := w2.Flush(); err != nil {
+		t.Errorf("Expected no error flushing, got %v instead", err)
+	}
+
+	var want = "[b] one\n[b] two\n[a] hello world\n[b] three\n"
+
+	if b.String() != want {
+		t.Errorf("Wanted %q, got %q instead", want, b.String())
+	}
+}
+
+func exitError(code string) *ExitError {
+	return &ExitError{
+		Details: kubernetes.StatusDetails{
+			Causes: []kubernetes.StatusCause{
+				{Type: "ExitCode", Message: code},
+			},
+		},
+	}
+}
+
+func TestAggregateExitCode(t *testing.T) {
+	var results = []InstanceResult{
+		newInstanceResult("a", nil),
+		newInstanceResult("b", exitError("0")),
+		newInstanceResult("c", exitError("2")),
+		newInstanceResult("d", exitError("1")),
+	}
+
+	if results[1].Err != nil {
+		t.Errorf("Expected successful exit not to be an error, got %v instead", results[1].Err)
+	}
+
+	if got := AggregateExitCode(results); got != 2 {
+		t.Errorf("Expected aggregated exit code to be 2, got %v instead", got)
+	}
+
+	results = append(results, newInstanceResult("e", errors.New("disconnected from gateway")))
+
+	if got := AggregateExitCode(results); got != ConnectionFailureExitCode {
+		t.Errorf("Expected aggregated exit code to be %v, got %v instead", ConnectionFailureExitCode, got)
+	}
+
+	if got := AggregateExitCode(results[:2]); got != 0 {
+		t.Errorf("Expected aggregated exit code to be 0, got %v instead", got)
+	}
+}
@@ -3,6 +3,7 @@ package shell
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/hashicorp/errwrap"
@@ -24,9 +25,17 @@ type Process struct {
 	TTY         bool
 	AttachStdin bool
 
+	// Stdin, Stdout, and Stderr default to the standard streams when nil
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
 	PID      int
 	ExitCode int
 
+	// Instance the process is running on, once started
+	Instance string
+
 	conn  *gosocketio.Client
 	shell *gosocketio.Namespace
 
@@ -71,10 +80,38 @@ func (p *Process) Run(ctx context.Context, conn *gosocketio.Client) (err error)
 	case err := <-runErr:
 		return err
 	case <-p.conn.Done():
-		return p.conn.Err()
+		if err := p.conn.Err(); err != nil {
+			return &TransportError{err}
+		}
+
+		return nil
 	}
 }
 
+func (p *Process) stdin() io.Reader {
+	if p.Stdin == nil {
+		return os.Stdin
+	}
+
+	return p.Stdin
+}
+
+func (p *Process) stdout() io.Writer {
+	if p.Stdout == nil {
+		return os.Stdout
+	}
+
+	return p.Stdout
+}
+
+func (p *Process) stderr() io.Writer {
+	if p.Stderr == nil {
+		return os.Stderr
+	}
+
+	return p.Stderr
+}
+
 func (p *Process) run(ctx context.Context, conn *gosocketio.Client) error {
 	readyToStartExec := make(chan struct{}, 1)
 
@@ -127,7 +164,7 @@ func (p *Process) run(ctx context.Context, conn *gosocketio.Client) error {
 
 		if _, ok := err.(*ExitError); !ok {
 			// add a line break to separate connection errors from other messages
-			_, _ = fmt.Fprintln(os.Stderr)
+			_, _ = fmt.Fprintln(p.stderr())
 		}
 
 		return err
@@ -140,6 +177,7 @@ func (p *Process) waitExecStarted() error {
 	var cerr = make(chan error, 1)
 
 	if err := p.shell.On("execStarted", func(es *execStarted) {
+		p.Instance = es.Instance
 		p.printInfo(es)
 		cerr <- nil
 		p.execStarted <- struct{}{}
@@ -207,5 +245,5 @@ func (p *Process) printInfo(es *execStarted) {
 		return
 	}
 
-	_, _ = fmt.Fprintln(os.Stderr, info)
+	_, _ = fmt.Fprintln(p.stderr(), info)
 }
@@ -0,0 +1,125 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TransportError is used when the connection to the shell gateway fails
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("connection lost: %v", e.Err)
+}
+
+// IsTransportError checks if the error is a connection failure, rather than a failure of the process
+func IsTransportError(err error) bool {
+	_, ok := err.(*TransportError)
+	return ok
+}
+
+// Reconnect runs a command again on the same instance when the connection is lost.
+// The process might still be running after the connection is lost, so it must only be used
+// for commands that are safe to run again, such as following the output of a detached job (see Attach).
+type Reconnect struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+
+	// Update is called before each attempt with parameters and the command to run,
+	// allowing changes such as resuming output from where it stopped
+	Update func(params *Params, cmd *string, args *[]string)
+
+	// Status is called with messages about the reconnection
+	Status func(msg string)
+
+	sleep func(ctx context.Context, d time.Duration) error
+	run   func(ctx context.Context, params Params, cmd string, args ...string) (string, error)
+}
+
+// DefaultReconnect settings
+var DefaultReconnect = Reconnect{
+	MaxAttempts: 5,
+	Backoff:     time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+// Run command, reconnecting on transport failures
+func (r *Reconnect) Run(ctx context.Context, params Params, cmd string, args ...string) error {
+	var run = r.run
+
+	if run == nil {
+		run = RunInstance
+	}
+
+	var sleep = r.sleep
+
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	for attempt := 0; ; attempt++ {
+		if r.Update != nil {
+			r.Update(&params, &cmd, &args)
+		}
+
+		instance, err := run(ctx, params, cmd, args...)
+
+		// the instance is unknown if the connection is lost before the process starts,
+		// and running the command again might make it run on another instance
+		if !IsTransportError(err) || ctx.Err() != nil || instance == "" {
+			return err
+		}
+
+		// reconnect to the same instance, even if it was chosen by the server
+		params.Instance = instance
+
+		if attempt >= r.MaxAttempts {
+			return err
+		}
+
+		var wait = r.backoff(attempt)
+
+		r.status(fmt.Sprintf("%v. Reconnecting to instance %s in %v (attempt %d of %d)...",
+			err, trimInstance(instance), wait, attempt+1, r.MaxAttempts))
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Reconnect) backoff(attempt int) time.Duration {
+	var d = r.Backoff
+
+	for i := 0; i < attempt; i++ {
+		d *= 2
+
+		if r.MaxBackoff != 0 && d >= r.MaxBackoff {
+			return r.MaxBackoff
+		}
+	}
+
+	return d
+}
+
+func (r *Reconnect) status(msg string) {
+	if r.Status != nil {
+		r.Status(msg)
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	var t = time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReconnect(t *testing.T) {
+	var calls []Params
+	var waits []time.Duration
+	var statuses []string
+
+	var r = Reconnect{
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+		MaxBackoff:  3 * time.Second,
+		Status: func(msg string) {
+			statuses = append(statuses, msg)
+		},
+		sleep: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+		run: func(ctx context.Context, params Params, cmd string, args ...string) (string, error) {
+			calls = append(calls, params)
+
+			if len(calls) < 4 {
+				return "abc123def456789", &TransportError{errors.New("disconnected from gateway")}
+			}
+
+			return params.Instance, exitError("3")
+		},
+	}
+
+	var err = r.Run(context.Background(), Params{}, "ls")
+
+	if ee, ok := err.(*ExitError); !ok || ee.Error() != "process terminated: 3" {
+		t.Errorf("Expected exit error, got %v instead", err)
+	}
+
+	if len(calls) != 4 {
+		t.Fatalf("Expected 4 attempts, got %d instead", len(calls))
+	}
+
+	if calls[0].Instance != "" || calls[3].Instance != "abc123def456789" {
+		t.Errorf("Expected reconnections to use the same instance, got %+v instead", calls)
+	}
+
+	var want = []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+
+	if !reflect.DeepEqual(want, waits) {
+		t.Errorf("Wanted waits %v, got %v instead", want, waits)
+	}
+
+	var wantStatus = "connection lost: disconnected from gateway. Reconnecting to instance abc123def456 in 1s (attempt 1 of 5)..."
+
+	if len(statuses) != 3 || statuses[0] != wantStatus {
+		t.Errorf("Wanted first status to be %q, got %q instead", wantStatus, statuses)
+	}
+}
+
+func TestReconnectUnknownInstance(t *testing.T) {
+	var attempts int
+	var transportErr = &TransportError{errors.New("disconnected from gateway")}
+
+	var r = Reconnect{
+		MaxAttempts: 5,
+		run: func(ctx context.Context, params Params, cmd string, args ...string) (string, error) {
+			attempts++
+			return "", transportErr
+		},
+	}
+
+	if err := r.Run(context.Background(), Params{}, "ls"); err != transportErr {
+		t.Errorf("Expected transport error, got %v instead", err)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected no reconnection when the instance is unknown, got %d attempts instead", attempts)
+	}
+}
+
+func TestReconnectMaxAttempts(t *testing.T) {
+	var attempts int
+
+	var r = Reconnect{
+		MaxAttempts: 2,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			return nil
+		},
+		run: func(ctx context.Context, params Params, cmd string, args ...string) (string, error) {
+			attempts++
+			return "abc", &TransportError{errors.New("disconnected from gateway")}
+		},
+	}
+
+	if err := r.Run(context.Background(), Params{}, "ls"); !IsTransportError(err) {
+		t.Errorf("Expected transport error, got %v instead", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d instead", attempts)
+	}
+}
@@ -0,0 +1,42 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+)
+
+// DefaultInterpreter for scripts without a shebang line
+const DefaultInterpreter = "sh"
+
+// scriptRunner receives the script base64 encoded on stdin, so special characters aren't mangled,
+// and runs it directly when no interpreter is given, relying on its shebang line.
+const scriptRunner = `f=$(mktemp) || exit; trap 'rm -f "$f"' EXIT
+base64 -d > "$f" || exit; chmod +x "$f"
+i="$1"; shift
+if [ -z "$i" ]; then "$f" "$@"; else $i "$f" "$@"; fi`
+
+// Interpreter for a script: empty when it has a shebang line, DefaultInterpreter otherwise
+func Interpreter(script []byte) string {
+	if bytes.HasPrefix(script, []byte("#!")) {
+		return ""
+	}
+
+	return DefaultInterpreter
+}
+
+// ScriptCommand returns the command for running a script received on stdin with an interpreter
+func ScriptCommand(interpreter string, args []string) (string, []string) {
+	return "sh", append([]string{"-c", scriptRunner, "sh", interpreter}, args...)
+}
+
+// RunScript uploads a script through stdin and runs it on the instance
+func RunScript(ctx context.Context, params Params, script []byte, interpreter string, args ...string) error {
+	params.AttachStdin = true
+	params.TTY = false
+	params.Stdin = strings.NewReader(base64.StdEncoding.EncodeToString(script) + "\n")
+
+	var cmd, cmdArgs = ScriptCommand(interpreter, args)
+	return Run(ctx, params, cmd, cmdArgs...)
+}
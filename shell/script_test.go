@@ -0,0 +1,73 @@
+package shell
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestInterpreter(t *testing.T) {
+	if got := Interpreter([]byte("#!/bin/bash\necho hi")); got != "" {
+		t.Errorf("Expected scripts with shebang to run directly, got interpreter %v instead", got)
+	}
+
+	if got := Interpreter([]byte("echo hi")); got != DefaultInterpreter {
+		t.Errorf("Expected default interpreter, got %v instead", got)
+	}
+}
+
+func runScriptLocally(t *testing.T, script, interpreter string, args ...string) (string, int) {
+	if _, err := exec.LookPath("base64"); err != nil {
+		t.Skip("base64 not found")
+	}
+
+	var cmd, cmdArgs = ScriptCommand(interpreter, args)
+	var c = exec.Command(cmd, cmdArgs...) // #nosec
+	var b bytes.Buffer
+
+	c.Stdin = strings.NewReader(base64.StdEncoding.EncodeToString([]byte(script)) + "\n")
+	c.Stdout = &b
+	c.Stderr = &b
+
+	var err = c.Run()
+
+	if ee, ok := err.(*exec.ExitError); ok {
+		return b.String(), ee.ExitCode()
+	}
+
+	if err != nil {
+		t.Fatalf("Expected no error running script, got %v instead", err)
+	}
+
+	return b.String(), 0
+}
+
+func TestScriptCommand(t *testing.T) {
+	var script = "echo \"args: $# $1 $2\"\n" +
+		"cat <<'END'\n" +
+		"heredoc with $pecial \"characters\" `and` \\ ünïcode\n" +
+		"END\n" +
+		"exit 3\n"
+
+	out, code := runScriptLocally(t, script, "sh", "one", "two words")
+
+	if code != 3 {
+		t.Errorf("Expected exit code 3, got %d instead", code)
+	}
+
+	var want = "args: 2 one two words\nheredoc with $pecial \"characters\" `and` \\ ünïcode\n"
+
+	if out != want {
+		t.Errorf("Wanted output %q, got %q instead", want, out)
+	}
+}
+
+func TestScriptCommandShebang(t *testing.T) {
+	out, code := runScriptLocally(t, "#!/bin/sh\necho shebang \"$1\"\n", "", "x")
+
+	if code != 0 || out != "shebang x\n" {
+		t.Errorf("Expected script to run with its shebang, got %q (exit code %d) instead", out, code)
+	}
+}
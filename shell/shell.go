@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"strconv"
 	"strings"
@@ -68,15 +69,29 @@ type Params struct {
 
 	AttachStdin bool
 	TTY         bool
+
+	// Stdin, Stdout, and Stderr default to the standard streams when nil
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // Run shell command.
 func Run(ctx context.Context, params Params, cmd string, args ...string) error {
+	_, err := RunInstance(ctx, params, cmd, args...)
+	return err
+}
+
+// RunInstance runs a shell command, returning the instance it ran on.
+func RunInstance(ctx context.Context, params Params, cmd string, args ...string) (instance string, err error) {
 	var process = &Process{
 		Cmd:         cmd,
 		Args:        args,
 		TTY:         params.TTY,
 		AttachStdin: params.AttachStdin,
+		Stdin:       params.Stdin,
+		Stdout:      params.Stdout,
+		Stderr:      params.Stderr,
 	}
 
 	var query = url.Values{}
@@ -113,10 +128,18 @@ func Run(ctx context.Context, params Params, cmd string, args ...string) error {
 	conn, err := gosocketio.ConnectContext(ctx, u, t)
 
 	if err != nil {
-		return err
+		return params.Instance, &TransportError{err}
+	}
+
+	err = process.Run(ctx, conn)
+
+	if process.Instance != "" {
+		instance = process.Instance
+	} else {
+		instance = params.Instance
 	}
 
-	return process.Run(ctx, conn)
+	return instance, err
 }
 
 func getCmdWithArgs(cmd string, args []string) string {
@@ -1,6 +1,11 @@
 package timehelper
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // RoundDuration rounds the duration to the nearest interval value
 // from https://play.golang.org/p/QHocTHl8iR
@@ -30,3 +35,57 @@ func RoundDuration(duration, round time.Duration) time.Duration {
 
 	return duration
 }
+
+// ParseMoment parses a moment in time given as a Unix timestamp, a date (2006-01-02),
+// a RFC 3339 time, or a duration relative to now (i.e., 20min, 3h, 30d).
+func ParseMoment(value string, now time.Time) (time.Time, error) {
+	return parseMoment(value, now, false)
+}
+
+// ParseEndMoment is like ParseMoment, but a date is parsed as the end of that day.
+// It should be used for the end of time ranges (i.e., --until 2019-09-30 includes the whole day).
+func ParseEndMoment(value string, now time.Time) (time.Time, error) {
+	return parseMoment(value, now, true)
+}
+
+func parseMoment(value string, now time.Time, endOfDay bool) (time.Time, error) {
+	if num, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(num, 0), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+
+		return t, nil
+	}
+
+	var d, err = parseDuration(value)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`invalid moment "%s": use a duration (i.e., 20min, 3h, 30d), a date (yyyy-mm-dd), or a Unix timestamp`, value)
+	}
+
+	return now.Add(-d), nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.Replace(value, "min", "m", -1)
+
+	if !strings.HasSuffix(value, "d") {
+		return time.ParseDuration(value)
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(days) * 24 * time.Hour, nil
+}
@@ -69,3 +69,65 @@ func TestRoundDurationText(t *testing.T) {
 		t.Errorf("Expected duration to be equal to template")
 	}
 }
+
+func TestParseMoment(t *testing.T) {
+	var now = time.Date(2019, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	var cases = map[string]time.Time{
+		"1470422556":           time.Unix(1470422556, 0),
+		"2019-09-01T10:00:00Z": time.Date(2019, 9, 1, 10, 0, 0, 0, time.UTC),
+		"2019-09-01":           time.Date(2019, 9, 1, 0, 0, 0, 0, time.Local),
+		"3h":                   now.Add(-3 * time.Hour),
+		"20min":                now.Add(-20 * time.Minute),
+		"30d":                  now.Add(-30 * 24 * time.Hour),
+	}
+
+	for value, want := range cases {
+		got, err := ParseMoment(value, now)
+
+		if err != nil {
+			t.Errorf("Expected no error parsing %v, got %v instead", value, err)
+		}
+
+		if !want.Equal(got) {
+			t.Errorf("Expected %v to be parsed as %v, got %v instead", value, want, got)
+		}
+	}
+}
+
+func TestParseEndMoment(t *testing.T) {
+	var now = time.Date(2019, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	var cases = map[string]time.Time{
+		"1470422556":           time.Unix(1470422556, 0),
+		"2019-09-01T10:00:00Z": time.Date(2019, 9, 1, 10, 0, 0, 0, time.UTC),
+		"2019-09-30":           time.Date(2019, 9, 30, 23, 59, 59, 999999999, time.Local),
+		"3h":                   now.Add(-3 * time.Hour),
+	}
+
+	for value, want := range cases {
+		got, err := ParseEndMoment(value, now)
+
+		if err != nil {
+			t.Errorf("Expected no error parsing %v, got %v instead", value, err)
+		}
+
+		if !want.Equal(got) {
+			t.Errorf("Expected %v to be parsed as %v, got %v instead", value, want, got)
+		}
+	}
+
+	var lastMoment = time.Date(2019, 9, 30, 23, 30, 0, 0, time.Local)
+
+	if got, _ := ParseEndMoment("2019-09-30", now); lastMoment.After(got) {
+		t.Errorf("Expected %v to be included until the end of the day, got %v instead", lastMoment, got)
+	}
+}
+
+func TestParseMomentFailure(t *testing.T) {
+	for _, value := range []string{"", "dog", "xd", "2019-13-01"} {
+		if _, err := ParseMoment(value, time.Now()); err == nil {
+			t.Errorf("Expected error parsing %v, got nil instead", value)
+		}
+	}
+}
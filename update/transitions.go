@@ -7,6 +7,7 @@ import (
 	"runtime"
 
 	"github.com/hashicorp/errwrap"
+	"github.com/henvic/wedeploycli/config"
 	"github.com/henvic/wedeploycli/defaults"
 	"github.com/henvic/wedeploycli/userhome"
 	"github.com/henvic/wedeploycli/verbose"
@@ -16,7 +17,7 @@ import (
 type Transition struct {
 	Description func() string
 	Test        func(pastVersion string) bool
-	Apply       func() error
+	Apply       func(conf *config.Config) error
 }
 
 var autocompleteTransition = Transition{
@@ -26,7 +27,7 @@ var autocompleteTransition = Transition{
 	Test: func(pastVersion string) bool {
 		return true
 	},
-	Apply: func() error {
+	Apply: func(conf *config.Config) error {
 		if runtime.GOOS == "windows" {
 			return nil
 		}
@@ -41,13 +42,26 @@ var autocompleteTransition = Transition{
 	},
 }
 
+var credentialsTransition = Transition{
+	Description: func() string {
+		return "move plaintext tokens to the credentials store"
+	},
+	Test: func(pastVersion string) bool {
+		return true
+	},
+	Apply: func(conf *config.Config) error {
+		return conf.MigrateCredentials()
+	},
+}
+
 var transitions = []Transition{
 	autocompleteTransition,
+	credentialsTransition,
 }
 
 // ApplyTransitions applies transition / fixes functions for updating this tool after updates
 // It is assumed that this is called only manually, but it is not guaranteed
-func ApplyTransitions(pastVersion string) {
+func ApplyTransitions(conf *config.Config, pastVersion string) {
 	if len(pastVersion) == 0 || pastVersion == defaults.Version || pastVersion == "master" {
 		return
 	}
@@ -61,7 +75,7 @@ func ApplyTransitions(pastVersion string) {
 				"Applying transition \"%v\" for past version %v",
 				description,
 				pastVersion))
-			if err := t.Apply(); err != nil {
+			if err := t.Apply(conf); err != nil {
 				_, _ = fmt.Fprintf(os.Stderr, "Error trying to apply transition \"%v\" for past version %v: %v\n",
 					description,
 					pastVersion,
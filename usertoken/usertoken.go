@@ -1,19 +1,103 @@
 package usertoken
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/hashicorp/errwrap"
 )
 
-// JSONWebToken for the user
+// ExpiryWarning is the default for how long before expiring a token is considered close to expiry
+var ExpiryWarning = 24 * time.Hour
+
+// ErrExpired is used when the token has expired
+var ErrExpired = errors.New("session expired")
+
+// JSONWebToken for the user.
+// Dates are in seconds, and might have a fractional part (see NumericDate on RFC 7519).
 type JSONWebToken struct {
-	Email string `json:"sub"`
-	UID   string `json:"uid"`
+	Email     string  `json:"sub"`
+	UID       string  `json:"uid"`
+	ExpiresAt float64 `json:"exp,omitempty"`
+	IssuedAt  float64 `json:"iat,omitempty"`
+}
+
+// Expires returns when the token expires, if it has an expiry claim
+func (j JSONWebToken) Expires() (t time.Time, ok bool) {
+	if j.ExpiresAt == 0 {
+		return t, false
+	}
+
+	return numericDate(j.ExpiresAt), true
+}
+
+// Issued returns when the token was issued, if it has an issued at claim
+func (j JSONWebToken) Issued() (t time.Time, ok bool) {
+	if j.IssuedAt == 0 {
+		return t, false
+	}
+
+	return numericDate(j.IssuedAt), true
+}
+
+func numericDate(seconds float64) time.Time {
+	var s, frac = math.Modf(seconds)
+	return time.Unix(int64(s), int64(frac*float64(time.Second)))
+}
+
+// Remaining lifetime of the token at the given time (negative when expired).
+// Tokens without an expiry claim never expire.
+func (j JSONWebToken) Remaining(now time.Time) (d time.Duration, ok bool) {
+	var expires, has = j.Expires()
+
+	if !has {
+		return 0, false
+	}
+
+	return expires.Sub(now), true
+}
+
+// Check the token lifetime at the given time, returning ErrExpired if it has expired.
+// It warns if the remaining lifetime is shorter than the warning duration.
+func (j JSONWebToken) Check(now time.Time, warning time.Duration) (remaining time.Duration, warn bool, err error) {
+	remaining, ok := j.Remaining(now)
+
+	switch {
+	case !ok:
+		return 0, false, nil
+	case remaining <= 0:
+		return remaining, false, ErrExpired
+	}
+
+	return remaining, remaining < warning, nil
+}
+
+// DescribeLifetime at the given time (i.e., "expires in 3h 20m (Mon, 19 Oct 2026 10:00:00 UTC)"),
+// or an empty string if the token has no expiry claim
+func (j JSONWebToken) DescribeLifetime(now time.Time) string {
+	var remaining, ok = j.Remaining(now)
+
+	if !ok {
+		return ""
+	}
+
+	var expires, _ = j.Expires()
+	var at = expires.In(now.Location()).Format(time.RFC1123)
+
+	if remaining <= 0 {
+		return fmt.Sprintf("expired %s ago (%s)", FormatLifetime(remaining), at)
+	}
+
+	return fmt.Sprintf("expires in %s (%s)", FormatLifetime(remaining), at)
 }
 
 type jsonWebToken JSONWebToken
 
 // Valid function for the JWT token
+// Expired tokens are still parsed, so that the expiry can be shown.
 func (j jsonWebToken) Valid() error {
 	return nil
 }
@@ -26,6 +110,28 @@ func ParseUnsignedJSONWebToken(accessToken string) (JSONWebToken, error) {
 	return JSONWebToken(claims), err
 }
 
+// FormatLifetime for humans, using the two most significant units (i.e., 2d 3h, 3h 20m, 5m)
+func FormatLifetime(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	var days = int(d / (24 * time.Hour))
+	var hours = int(d % (24 * time.Hour) / time.Hour)
+	var minutes = int(d % time.Hour / time.Minute)
+
+	switch {
+	case days != 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours != 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes != 0:
+		return fmt.Sprintf("%dm", minutes)
+	}
+
+	return "less than a minute"
+}
+
 func keyFunc(token *jwt.Token) (interface{}, error) {
 	return []byte{}, nil
 }
@@ -0,0 +1,138 @@
+package usertoken
+
+import (
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+var now = time.Date(2020, 3, 20, 10, 0, 0, 0, time.UTC)
+
+func sign(t *testing.T, claims jwt.MapClaims) string {
+	var s, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestParseUnsignedJSONWebToken(t *testing.T) {
+	var token = sign(t, jwt.MapClaims{
+		"sub": "foo@example.com",
+		"uid": "123",
+		"iat": now.Add(-time.Hour).Unix(),
+		"exp": now.Add(-time.Minute).Unix(),
+	})
+
+	var got, err = ParseUnsignedJSONWebToken(token)
+
+	if err != nil {
+		t.Fatalf("Expected no error parsing expired token, got %v instead", err)
+	}
+
+	var want = JSONWebToken{
+		Email:     "foo@example.com",
+		UID:       "123",
+		IssuedAt:  float64(now.Add(-time.Hour).Unix()),
+		ExpiresAt: float64(now.Add(-time.Minute).Unix()),
+	}
+
+	if got != want {
+		t.Errorf("Wanted %+v, got %+v instead", want, got)
+	}
+
+	if issued, ok := got.Issued(); !ok || !issued.Equal(now.Add(-time.Hour)) {
+		t.Errorf("Expected token to be issued an hour ago, got %v instead", issued)
+	}
+}
+
+func TestParseUnsignedJSONWebTokenFractionalDates(t *testing.T) {
+	var token = sign(t, jwt.MapClaims{
+		"sub": "foo@example.com",
+		"iat": 1571234500.25,
+		"exp": 1571234567.5,
+	})
+
+	var got, err = ParseUnsignedJSONWebToken(token)
+
+	if err != nil {
+		t.Fatalf("Expected no error parsing token with fractional dates, got %v instead", err)
+	}
+
+	if got.Email != "foo@example.com" {
+		t.Errorf("Expected email to be foo@example.com, got %v instead", got.Email)
+	}
+
+	var want = time.Unix(1571234567, int64(500*time.Millisecond))
+
+	if expires, ok := got.Expires(); !ok || !expires.Equal(want) {
+		t.Errorf("Wanted token to expire on %v, got %v instead", want, expires)
+	}
+
+	want = time.Unix(1571234500, int64(250*time.Millisecond))
+
+	if issued, ok := got.Issued(); !ok || !issued.Equal(want) {
+		t.Errorf("Wanted token to be issued on %v, got %v instead", want, issued)
+	}
+}
+
+func TestParseUnsignedJSONWebTokenInvalid(t *testing.T) {
+	if _, err := ParseUnsignedJSONWebToken("token"); err == nil {
+		t.Errorf("Expected error parsing invalid token, got nil instead")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var cases = []struct {
+		token     JSONWebToken
+		remaining time.Duration
+		warn      bool
+		err       error
+	}{
+		{
+			token: JSONWebToken{},
+		},
+		{
+			token:     JSONWebToken{ExpiresAt: float64(now.Add(72 * time.Hour).Unix())},
+			remaining: 72 * time.Hour,
+		},
+		{
+			token:     JSONWebToken{ExpiresAt: float64(now.Add(3 * time.Hour).Unix())},
+			remaining: 3 * time.Hour,
+			warn:      true,
+		},
+		{
+			token:     JSONWebToken{ExpiresAt: float64(now.Add(-time.Minute).Unix())},
+			remaining: -time.Minute,
+			err:       ErrExpired,
+		},
+	}
+
+	for _, c := range cases {
+		remaining, warn, err := c.token.Check(now, ExpiryWarning)
+
+		if remaining != c.remaining || warn != c.warn || err != c.err {
+			t.Errorf("Wanted (%v, %v, %v) for %+v, got (%v, %v, %v) instead",
+				c.remaining, c.warn, c.err, c.token, remaining, warn, err)
+		}
+	}
+}
+
+func TestDescribeLifetime(t *testing.T) {
+	var cases = map[int64]string{
+		0: "",
+		now.Add(50*time.Hour + time.Minute).Unix():   "expires in 2d 2h (Sun, 22 Mar 2020 12:01:00 UTC)",
+		now.Add(3*time.Hour + 20*time.Minute).Unix(): "expires in 3h 20m (Fri, 20 Mar 2020 13:20:00 UTC)",
+		now.Add(30 * time.Second).Unix():             "expires in less than a minute (Fri, 20 Mar 2020 10:00:30 UTC)",
+		now.Add(-5 * time.Minute).Unix():             "expired 5m ago (Fri, 20 Mar 2020 09:55:00 UTC)",
+	}
+
+	for exp, want := range cases {
+		if got := (JSONWebToken{ExpiresAt: float64(exp)}).DescribeLifetime(now); got != want {
+			t.Errorf("Wanted %q, got %q instead", want, got)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package verbosereq
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/henvic/wedeploycli/envmask"
+)
+
+// EnvMasker for hiding environment variable values on request and response bodies.
+// The WEDEPLOY_UNSAFE_VERBOSE environment variable overrides this.
+var EnvMasker = envmask.Masker{
+	Patterns: envmask.DefaultPatterns,
+}
+
+const envPath = "/environment-variables/"
+
+func responseURL(response *http.Response) string {
+	if response.Request == nil || response.Request.URL == nil {
+		return ""
+	}
+
+	return response.Request.URL.String()
+}
+
+// envKeyFromURL returns the environment variable key of a single variable endpoint
+func envKeyFromURL(u string) string {
+	var pu, err = url.Parse(u)
+
+	if err != nil {
+		return ""
+	}
+
+	var i = strings.LastIndex(pu.Path, envPath)
+
+	if i == -1 {
+		return ""
+	}
+
+	return strings.Trim(pu.Path[i+len(envPath):], "/")
+}
+
+// maskEnvBody masks environment variable values on JSON bodies.
+// The body is returned unmodified if it isn't JSON or there is nothing to mask.
+func maskEnvBody(u string, body []byte) []byte {
+	if unsafeVerbose || EnvMasker.Reveal {
+		return body
+	}
+
+	var v interface{}
+	var d = json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+
+	if err := d.Decode(&v); err != nil {
+		return body
+	}
+
+	var masked = maskEnvValues(v)
+
+	if m, ok := v.(map[string]interface{}); ok {
+		if key := envKeyFromURL(u); key != "" && maskEnvValue(m, "value", key) {
+			masked = true
+		}
+	}
+
+	if !masked {
+		return body
+	}
+
+	var b bytes.Buffer
+	var e = json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+
+	if err := e.Encode(v); err != nil {
+		return body
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+// maskEnvValues recursively, considering env maps and name/value pairs
+func maskEnvValues(v interface{}) (masked bool) {
+	switch vv := v.(type) {
+	case []interface{}:
+		for _, item := range vv {
+			masked = maskEnvValues(item) || masked
+		}
+	case map[string]interface{}:
+		if name, ok := vv["name"].(string); ok {
+			masked = maskEnvValue(vv, "value", name) || masked
+		}
+
+		if env, ok := vv["env"].(map[string]interface{}); ok {
+			for k := range env {
+				masked = maskEnvValue(env, k, k) || masked
+			}
+		}
+
+		for k, item := range vv {
+			if k != "env" {
+				masked = maskEnvValues(item) || masked
+			}
+		}
+	}
+
+	return masked
+}
+
+func maskEnvValue(m map[string]interface{}, field, name string) bool {
+	var value, ok = m[field].(string)
+
+	if !ok || !EnvMasker.Masked(name) || value == "" {
+		return false
+	}
+
+	m[field] = envmask.Placeholder
+	return true
+}
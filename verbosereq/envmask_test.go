@@ -0,0 +1,103 @@
+package verbosereq
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/henvic/wedeploy-sdk-go"
+	"github.com/henvic/wedeploycli/servertest"
+)
+
+func TestMaskEnvBody(t *testing.T) {
+	var cases = []struct {
+		url  string
+		body string
+		want string
+	}{
+		{
+			"http://www.example.com/projects/p/services/s/environment-variables/",
+			`{"env":{"DB_HOST":"db","DB_PASSWORD":"abc"}}`,
+			`{"env":{"DB_HOST":"db","DB_PASSWORD":"********"}}`,
+		},
+		{
+			"http://www.example.com/projects/p/services/s/environment-variables/API_TOKEN",
+			`{"value":"abc"}`,
+			`{"value":"********"}`,
+		},
+		{
+			"http://www.example.com/projects/p/services/s/environment-variables/DB_HOST",
+			`{"value":"db"}`,
+			`{"value":"db"}`,
+		},
+		{
+			"http://www.example.com/projects/p/services/s/environment-variables",
+			`[{"name":"DB_HOST","value":"db"},{"name":"SECRET","value":"abc"}]`,
+			`[{"name":"DB_HOST","value":"db"},{"name":"SECRET","value":"********"}]`,
+		},
+		{
+			"http://www.example.com/foo",
+			`{"bar": "one"}`,
+			`{"bar": "one"}`,
+		},
+		{
+			"http://www.example.com/foo",
+			`not json`,
+			`not json`,
+		},
+	}
+
+	for _, c := range cases {
+		if got := string(maskEnvBody(c.url, []byte(c.body))); got != c.want {
+			t.Errorf("Wanted body for %v to be %v, got %v instead", c.url, c.want, got)
+		}
+	}
+}
+
+func TestMaskEnvBodyUnsafe(t *testing.T) {
+	defer func() {
+		unsafeVerbose = false
+	}()
+
+	unsafeVerbose = true
+
+	var body = `{"env":{"DB_PASSWORD":"abc"}}`
+
+	if got := string(maskEnvBody("", []byte(body))); got != body {
+		t.Errorf("Expected body not to be masked on unsafe mode, got %v instead", got)
+	}
+}
+
+func TestRequestVerboseFeedbackMaskEnv(t *testing.T) {
+	bufErrStream.Reset()
+	servertest.Setup()
+
+	servertest.Mux.HandleFunc("/environment-variables/",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `[{"name": "APP_SECRET", "value": "shh"}]`)
+		})
+
+	var request = wedeploy.URL("http://www.example.com/environment-variables/")
+	request.Body(bytes.NewBufferString(`{"env":{"APP_SECRET":"shh"}}`))
+
+	if err := request.Put(); err != nil {
+		panic(err)
+	}
+
+	Feedback(request)
+
+	var got = bufErrStream.String()
+
+	if strings.Contains(got, "shh") {
+		t.Errorf("Expected secret value to be masked, got %v instead", got)
+	}
+
+	if !strings.Contains(got, `{"env":{"APP_SECRET":"********"}}`) {
+		t.Errorf("Expected masked request body, got %v instead", got)
+	}
+
+	servertest.Teardown()
+}
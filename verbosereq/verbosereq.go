@@ -45,7 +45,7 @@ func init() {
 }
 
 // debugRequestBody prints verbose messages when debugging is enabled
-func debugRequestBody(body io.Reader) {
+func debugRequestBody(u string, body io.Reader) {
 	if body != nil {
 		log("")
 	}
@@ -55,11 +55,11 @@ func debugRequestBody(body io.Reader) {
 	case *os.File:
 		debugFileReaderBody(body)
 	case *bytes.Buffer:
-		debugBufferReaderBody(body)
+		debugBufferReaderBody(u, body)
 	case *bytes.Reader:
-		debugBytesReaderBody(body)
+		debugBytesReaderBody(u, body)
 	case *strings.Reader:
-		debugStringsReaderBody(body)
+		debugStringsReaderBody(u, body)
 	default:
 		debugUnknownTypeBody(body)
 	}
@@ -71,11 +71,11 @@ func debugFileReaderBody(body io.Reader) {
 		color.Format(color.FgMagenta, "Sending file as request body:\n%v", fr.Name()))
 }
 
-func debugBufferReaderBody(body io.Reader) {
-	log(fmt.Sprintf("\n%s", body.(*bytes.Buffer)))
+func debugBufferReaderBody(u string, body io.Reader) {
+	log(fmt.Sprintf("\n%s", maskEnvBody(u, body.(*bytes.Buffer).Bytes())))
 }
 
-func debugBytesReaderBody(body io.Reader) {
+func debugBytesReaderBody(u string, body io.Reader) {
 	var br = body.(*bytes.Reader)
 	var b bytes.Buffer
 
@@ -87,10 +87,10 @@ func debugBytesReaderBody(body io.Reader) {
 		panic(err)
 	}
 
-	log("\n" + b.String())
+	log("\n" + string(maskEnvBody(u, b.Bytes())))
 }
 
-func debugStringsReaderBody(body io.Reader) {
+func debugStringsReaderBody(u string, body io.Reader) {
 	var sr = body.(*strings.Reader)
 	var b bytes.Buffer
 
@@ -102,7 +102,7 @@ func debugStringsReaderBody(body io.Reader) {
 		panic(err)
 	}
 
-	log("\n" + (b.String()))
+	log("\n" + string(maskEnvBody(u, b.Bytes())))
 }
 
 func debugUnknownTypeBody(body io.Reader) {
@@ -147,7 +147,7 @@ func requestVerboseFeedback(request *wedeploy.WeDeploy) {
 		color.Format(color.FgBlue, request.Request.Proto))
 
 	verbosePrintHeaders(request.Headers)
-	debugRequestBody(request.RequestBody)
+	debugRequestBody(request.URL, request.RequestBody)
 
 	log("\n")
 	feedbackResponse(request.Response)
@@ -190,7 +190,7 @@ func feedbackResponseBodyAll(response *http.Response, body []byte) {
 		err := json.Unmarshal(body, &json.RawMessage{})
 
 		if err == nil {
-			log(string(prettyjson.Pretty(body)))
+			log(string(prettyjson.Pretty(maskEnvBody(responseURL(response), body))))
 			return
 		}
 
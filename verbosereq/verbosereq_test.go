@@ -39,7 +39,7 @@ func TestMain(m *testing.M) {
 
 func TestDebugRequestBody(t *testing.T) {
 	bufErrStream.Reset()
-	debugRequestBody(nil)
+	debugRequestBody("", nil)
 
 	if bufErrStream.Len() != 0 {
 		t.Errorf("Wanted debug to be empty")